@@ -0,0 +1,127 @@
+package nexus
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMissingMetadata when an uploaded package doesn't contain the metadata
+// needed to identify it
+var ErrMissingMetadata = errors.New("package metadata not found")
+
+// maxMetadataSize limits how much of a metadata file is read from a package
+const maxMetadataSize = 1 << 20
+
+// localSource returns the content of src as an open file, spooling it to a
+// temporary file when it isn't one already, so the package can be inspected
+// before being uploaded. The returned source replays the same content and
+// cleanup must be called once the upload is done.
+func localSource(src UploadSource) (*os.File, UploadSource, func(), error) {
+	r, err := src.open()
+	if err != nil {
+		return nil, src, nil, err
+	}
+
+	if f, ok := r.(*os.File); ok {
+		return f, src, func() { f.Close() }, nil
+	}
+	defer r.Close()
+
+	tmp, err := ioutil.TempFile("", "nexus-upload-*")
+	if err != nil {
+		return nil, src, nil, err
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		cleanup()
+		return nil, src, nil, err
+	}
+
+	replay := FileSource(tmp.Name())
+	replay.Filename = src.Filename
+	replay.Size = size
+	return tmp, replay, cleanup, nil
+}
+
+// readZipFile returns the content of the first file in the zip archive for
+// which match returns true
+func readZipFile(f *os.File, match func(name string) bool) ([]byte, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range archive.File {
+		if !match(entry.Name) {
+			continue
+		}
+		r, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ioutil.ReadAll(io.LimitReader(r, maxMetadataSize))
+	}
+	return nil, ErrMissingMetadata
+}
+
+// readTarFile returns the content of the first file in the tar stream for
+// which match returns true
+func readTarFile(r io.Reader, match func(name string) bool) ([]byte, error) {
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil, ErrMissingMetadata
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg || !match(header.Name) {
+			continue
+		}
+		return ioutil.ReadAll(io.LimitReader(archive, maxMetadataSize))
+	}
+}
+
+// readTarGzFile is readTarFile for gzip compressed archives read from the
+// start of f
+func readTarGzFile(f *os.File, match func(name string) bool) ([]byte, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return readTarFile(gz, match)
+}
+
+// pathDepth returns the number of elements in the cleaned archive path
+func pathDepth(name string) int {
+	name = path.Clean(name)
+	depth := 1
+	for _, r := range name {
+		if r == '/' {
+			depth++
+		}
+	}
+	return depth
+}
@@ -1,19 +1,28 @@
 package nexus
 
 import (
-	"fmt"
+	"context"
+	"net/url"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
-// Asset object
+// Asset object, time fields are zero when Nexus doesn't know them
 type Asset struct {
-	DownloadURL string            `json:"downloadUrl"`
-	Path        string            `json:"path"`
-	ID          string            `json:"id"`
-	Repository  string            `json:"repository"`
-	Format      string            `json:"format"`
-	Checksum    map[string]string `json:"checksum"`
+	DownloadURL    string            `json:"downloadUrl"`
+	Path           string            `json:"path"`
+	ID             string            `json:"id"`
+	Repository     string            `json:"repository"`
+	Format         string            `json:"format"`
+	Checksum       map[string]string `json:"checksum"`
+	ContentType    string            `json:"contentType"`
+	LastModified   time.Time         `json:"lastModified"`
+	BlobCreated    time.Time         `json:"blobCreated"`
+	LastDownloaded time.Time         `json:"lastDownloaded"`
+	Uploader       string            `json:"uploader"`
+	UploaderIP     string            `json:"uploaderIp"`
+	FileSize       int64             `json:"fileSize"`
 }
 
 // AssetGroup object
@@ -29,6 +38,11 @@ type AssetGroup struct {
 
 // Assets list via endpoint
 func (c Client) Assets(repositoryID, continuationToken string) (assets []Asset, token string, err error) {
+	return c.AssetsContext(context.Background(), repositoryID, continuationToken)
+}
+
+// AssetsContext list via endpoint
+func (c Client) AssetsContext(ctx context.Context, repositoryID, continuationToken string) (assets []Asset, token string, err error) {
 	args := map[string]interface{}{
 		"repository":        repositoryID,
 		"continuationToken": continuationToken,
@@ -43,7 +57,7 @@ func (c Client) Assets(repositoryID, continuationToken string) (assets []Asset,
 		ContinuationToken string  `json:"continuationToken"`
 	}{}
 
-	err = c.makeRequest("GET", "/assets", args, &result)
+	err = c.makeRequest(ctx, "GET", "/assets", args, &result)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "Assets")
 	}
@@ -52,10 +66,30 @@ func (c Client) Assets(repositoryID, continuationToken string) (assets []Asset,
 
 // Asset lookup via endpoint
 func (c Client) Asset(id string) (*Asset, error) {
-	return nil, fmt.Errorf("missing")
+	return c.AssetContext(context.Background(), id)
+}
+
+// AssetContext lookup via endpoint
+func (c Client) AssetContext(ctx context.Context, id string) (*Asset, error) {
+	var result Asset
+	if err := c.makeRequest(ctx, "GET", "/assets/"+url.PathEscape(id), nil, &result); err != nil {
+		return nil, errors.Wrap(err, "Asset")
+	}
+	return &result, nil
 }
 
 // DeleteAsset via endpoint
 func (c Client) DeleteAsset(id string) error {
-	return fmt.Errorf("missing")
+	return c.DeleteAssetContext(context.Background(), id)
+}
+
+// DeleteAssetContext via endpoint
+func (c Client) DeleteAssetContext(ctx context.Context, id string) error {
+	if !c.authenticated() {
+		return errors.Wrap(ErrUnauthorized, "DeleteAsset: missing user authentication")
+	}
+	if err := c.makeRequest(ctx, "DELETE", "/assets/"+url.PathEscape(id), nil, nil); err != nil {
+		return errors.Wrap(err, "DeleteAsset")
+	}
+	return nil
 }
@@ -1,7 +1,11 @@
 package nexus
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -28,3 +32,33 @@ func TestAsset(t *testing.T) {
 	t.Logf("Results: %+v\n", asset)
 	// TODO: Check that asset is expected result
 }
+
+func TestDeleteAsset(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/assets/abc":
+			w.Write([]byte(`{"id":"abc","fileSize":9,"uploader":"admin","lastModified":"2020-01-02T03:04:05.000+00:00","lastDownloaded":null}`))
+		case r.Method == "DELETE" && r.URL.Path == "/assets/abc":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"))
+	asset, err := c.Asset("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.FileSize != 9 || asset.Uploader != "admin" || asset.LastModified.IsZero() || !asset.LastDownloaded.IsZero() {
+		t.Errorf("unexpected asset %+v", asset)
+	}
+
+	if err := c.DeleteAsset("abc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteAsset("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Blob store types
+const (
+	BlobStoreTypeFile = "File"
+	BlobStoreTypeS3   = "S3"
+)
+
+// Soft quota types, limits are in bytes
+const (
+	QuotaSpaceRemaining = "spaceRemainingQuota"
+	QuotaSpaceUsed      = "spaceUsedQuota"
+)
+
+// BlobStore summary as listed by Nexus
+type BlobStore struct {
+	Name                  string          `json:"name"`
+	Type                  string          `json:"type"`
+	Available             bool            `json:"available"`
+	BlobCount             int64           `json:"blobCount"`
+	TotalSizeInBytes      int64           `json:"totalSizeInBytes"`
+	AvailableSpaceInBytes int64           `json:"availableSpaceInBytes"`
+	SoftQuota             *BlobStoreQuota `json:"softQuota,omitempty"`
+}
+
+// BlobStoreQuota warns, without blocking writes, once the limit is crossed
+type BlobStoreQuota struct {
+	// Type is one of QuotaSpaceRemaining or QuotaSpaceUsed
+	Type  string `json:"type"`
+	Limit int64  `json:"limit"`
+}
+
+// BlobStoreQuotaStatus reports if a blob store is violating its soft quota
+type BlobStoreQuotaStatus struct {
+	BlobStoreName string `json:"blobStoreName"`
+	IsViolation   bool   `json:"isViolation"`
+	Message       string `json:"message"`
+}
+
+// FileBlobStore stores blobs on the Nexus server's file system
+type FileBlobStore struct {
+	Name string `json:"name,omitempty"`
+	// Path is absolute or relative to the Nexus data directory
+	Path      string          `json:"path"`
+	SoftQuota *BlobStoreQuota `json:"softQuota,omitempty"`
+}
+
+// S3BlobStore stores blobs in an S3 bucket
+type S3BlobStore struct {
+	Name                string                `json:"name"`
+	SoftQuota           *BlobStoreQuota       `json:"softQuota,omitempty"`
+	BucketConfiguration S3BucketConfiguration `json:"bucketConfiguration"`
+}
+
+// S3BucketConfiguration of an S3BlobStore
+type S3BucketConfiguration struct {
+	Bucket                   S3Bucket                    `json:"bucket"`
+	Encryption               *S3Encryption               `json:"encryption,omitempty"`
+	BucketSecurity           *S3BucketSecurity           `json:"bucketSecurity,omitempty"`
+	AdvancedBucketConnection *S3AdvancedBucketConnection `json:"advancedBucketConnection,omitempty"`
+}
+
+// S3Bucket location, Expiration is the number of days deleted blobs are kept
+type S3Bucket struct {
+	Region     string `json:"region"`
+	Name       string `json:"name"`
+	Prefix     string `json:"prefix,omitempty"`
+	Expiration int    `json:"expiration"`
+}
+
+// S3Encryption of the stored blobs, e.g. "s3ManagedEncryption" or "kmsManagedEncryption"
+type S3Encryption struct {
+	EncryptionType string `json:"encryptionType,omitempty"`
+	EncryptionKey  string `json:"encryptionKey,omitempty"`
+}
+
+// S3BucketSecurity credentials, leave unset to use the server's default chain
+type S3BucketSecurity struct {
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	Role            string `json:"role,omitempty"`
+	SessionToken    string `json:"sessionToken,omitempty"`
+}
+
+// S3AdvancedBucketConnection for S3 compatible services, e.g. MinIO
+type S3AdvancedBucketConnection struct {
+	Endpoint              string `json:"endpoint,omitempty"`
+	SignerType            string `json:"signerType,omitempty"`
+	ForcePathStyle        bool   `json:"forcePathStyle"`
+	MaxConnectionPoolSize *int   `json:"maxConnectionPoolSize,omitempty"`
+}
+
+// BlobStores list
+func (c Client) BlobStores(ctx context.Context) ([]BlobStore, error) {
+	var result []BlobStore
+	if err := c.makeRequest(ctx, "GET", "/blobstores", nil, &result); err != nil {
+		return nil, errors.Wrap(err, "BlobStores")
+	}
+	return result, nil
+}
+
+// BlobStore lookup by name, use FileBlobStore or S3BlobStore for the full
+// configuration
+func (c Client) BlobStore(ctx context.Context, name string) (*BlobStore, error) {
+	stores, err := c.BlobStores(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "BlobStore")
+	}
+	for _, store := range stores {
+		if store.Name == name {
+			return &store, nil
+		}
+	}
+	return nil, errors.Wrapf(ErrNotFound, "BlobStore: '%s'", name)
+}
+
+// FileBlobStore configuration lookup
+func (c Client) FileBlobStore(ctx context.Context, name string) (*FileBlobStore, error) {
+	var result FileBlobStore
+	if err := c.makeRequest(ctx, "GET", "/blobstores/file/"+url.PathEscape(name), nil, &result); err != nil {
+		return nil, errors.Wrap(err, "FileBlobStore")
+	}
+	// The name isn't part of the returned configuration
+	result.Name = name
+	return &result, nil
+}
+
+// CreateFileBlobStore from the given configuration
+func (c Client) CreateFileBlobStore(ctx context.Context, store FileBlobStore) error {
+	if err := c.writeBlobStore(ctx, "POST", "/blobstores/file", store.Name, store); err != nil {
+		return errors.Wrap(err, "CreateFileBlobStore")
+	}
+	return nil
+}
+
+// UpdateFileBlobStore replaces the configuration of an existing file blob store
+func (c Client) UpdateFileBlobStore(ctx context.Context, store FileBlobStore) error {
+	name := store.Name
+	store.Name = ""
+	if err := c.writeBlobStore(ctx, "PUT", "/blobstores/file/"+url.PathEscape(name), name, store); err != nil {
+		return errors.Wrap(err, "UpdateFileBlobStore")
+	}
+	return nil
+}
+
+// S3BlobStore configuration lookup
+func (c Client) S3BlobStore(ctx context.Context, name string) (*S3BlobStore, error) {
+	var result S3BlobStore
+	if err := c.makeRequest(ctx, "GET", "/blobstores/s3/"+url.PathEscape(name), nil, &result); err != nil {
+		return nil, errors.Wrap(err, "S3BlobStore")
+	}
+	return &result, nil
+}
+
+// CreateS3BlobStore from the given configuration
+func (c Client) CreateS3BlobStore(ctx context.Context, store S3BlobStore) error {
+	if err := c.writeBlobStore(ctx, "POST", "/blobstores/s3", store.Name, store); err != nil {
+		return errors.Wrap(err, "CreateS3BlobStore")
+	}
+	return nil
+}
+
+// UpdateS3BlobStore replaces the configuration of an existing S3 blob store
+func (c Client) UpdateS3BlobStore(ctx context.Context, store S3BlobStore) error {
+	if err := c.writeBlobStore(ctx, "PUT", "/blobstores/s3/"+url.PathEscape(store.Name), store.Name, store); err != nil {
+		return errors.Wrap(err, "UpdateS3BlobStore")
+	}
+	return nil
+}
+
+// writeBlobStore sends a blob store configuration after the common checks
+func (c Client) writeBlobStore(ctx context.Context, method, endpoint, name string, payload interface{}) error {
+	if !c.authenticated() {
+		return errors.Wrap(ErrUnauthorized, "missing user authentication")
+	}
+	if name == "" {
+		return fmt.Errorf("missing blob store name")
+	}
+	return c.makeJSONRequest(ctx, method, endpoint, nil, payload, nil)
+}
+
+// DeleteBlobStore, Nexus refuses while repositories still use it
+func (c Client) DeleteBlobStore(ctx context.Context, name string) error {
+	if !c.authenticated() {
+		return errors.Wrap(ErrUnauthorized, "DeleteBlobStore: missing user authentication")
+	}
+	if err := c.makeRequest(ctx, "DELETE", "/blobstores/"+url.PathEscape(name), nil, nil); err != nil {
+		return errors.Wrap(err, "DeleteBlobStore")
+	}
+	return nil
+}
+
+// BlobStoreQuotaStatus checks the blob store against its soft quota
+func (c Client) BlobStoreQuotaStatus(ctx context.Context, name string) (*BlobStoreQuotaStatus, error) {
+	var result BlobStoreQuotaStatus
+	if err := c.makeRequest(ctx, "GET", "/blobstores/"+url.PathEscape(name)+"/quota-status", nil, &result); err != nil {
+		return nil, errors.Wrap(err, "BlobStoreQuotaStatus")
+	}
+	return &result, nil
+}
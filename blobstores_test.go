@@ -0,0 +1,102 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestBlobStoreManagement(t *testing.T) {
+	var requests []string
+	var payload map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /blobstores":
+			json.NewEncoder(w).Encode([]BlobStore{{Name: "default", Type: BlobStoreTypeFile, Available: true, BlobCount: 3}})
+		case "GET /blobstores/file/default":
+			w.Write([]byte(`{"path":"default","softQuota":{"type":"spaceUsedQuota","limit":1024}}`))
+		case "GET /blobstores/default/quota-status":
+			w.Write([]byte(`{"blobStoreName":"default","isViolation":true,"message":"over quota"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"))
+	ctx := context.Background()
+
+	store, err := c.BlobStore(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Type != BlobStoreTypeFile || store.BlobCount != 3 {
+		t.Errorf("unexpected blob store %+v", store)
+	}
+	if _, err := c.BlobStore(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	file, err := c.FileBlobStore(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "default" || file.SoftQuota == nil || file.SoftQuota.Limit != 1024 {
+		t.Errorf("unexpected file blob store %+v", file)
+	}
+
+	file.SoftQuota.Type = QuotaSpaceRemaining
+	if err := c.UpdateFileBlobStore(ctx, *file); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := payload["name"]; ok || payload["softQuota"] == nil {
+		t.Errorf("unexpected payload %v", payload)
+	}
+
+	s3 := S3BlobStore{
+		Name: "s3",
+		BucketConfiguration: S3BucketConfiguration{
+			Bucket: S3Bucket{Region: "eu-west-1", Name: "artifacts", Expiration: 3},
+		},
+	}
+	if err := c.CreateS3BlobStore(ctx, s3); err != nil {
+		t.Fatal(err)
+	}
+	if payload["name"] != "s3" || payload["bucketConfiguration"] == nil {
+		t.Errorf("unexpected payload %v", payload)
+	}
+
+	status, err := c.BlobStoreQuotaStatus(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.IsViolation || status.Message != "over quota" {
+		t.Errorf("unexpected quota status %+v", status)
+	}
+
+	if err := c.DeleteBlobStore(ctx, "s3"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "GET /blobstores,GET /blobstores,GET /blobstores/file/default,PUT /blobstores/file/default," +
+		"POST /blobstores/s3,GET /blobstores/default/quota-status,DELETE /blobstores/s3"
+	if got := strings.Join(requests, ","); got != expected {
+		t.Errorf("unexpected requests %s", got)
+	}
+
+	if err := c.CreateFileBlobStore(ctx, FileBlobStore{Path: "nameless"}); err == nil {
+		t.Error("expected a missing name to be rejected")
+	}
+	anonymous, _ := New(ts.URL)
+	if err := anonymous.DeleteBlobStore(ctx, "default"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
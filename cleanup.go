@@ -0,0 +1,262 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Release types a CleanupPolicy can be limited to
+const (
+	ReleaseTypeReleases    = "RELEASES"
+	ReleaseTypePrereleases = "PRERELEASES"
+)
+
+// CleanupFormatAll applies a CleanupPolicy to repositories of any format
+const CleanupFormatAll = "ALL_FORMATS"
+
+// CleanupPolicy removes the components matching all of its criteria, unset
+// criteria match everything. Ages are in days.
+type CleanupPolicy struct {
+	Name   string `json:"name"`
+	Notes  string `json:"notes,omitempty"`
+	Format string `json:"format"`
+	// LastBlobUpdated matches components not updated in the given days
+	LastBlobUpdated *int `json:"criteriaLastBlobUpdated,omitempty"`
+	// LastDownloaded matches components not downloaded in the given days
+	LastDownloaded *int `json:"criteriaLastDownloaded,omitempty"`
+	// ReleaseType is one of ReleaseTypeReleases or ReleaseTypePrereleases
+	ReleaseType string `json:"criteriaReleaseType,omitempty"`
+	// AssetRegex matches components with any asset path matching it
+	AssetRegex string `json:"criteriaAssetRegex,omitempty"`
+}
+
+// CleanupPolicies list
+func (c Client) CleanupPolicies(ctx context.Context) ([]CleanupPolicy, error) {
+	var result []CleanupPolicy
+	if err := c.makeRequest(ctx, "GET", "/cleanup-policies", nil, &result); err != nil {
+		return nil, errors.Wrap(err, "CleanupPolicies")
+	}
+	return result, nil
+}
+
+// CleanupPolicy lookup
+func (c Client) CleanupPolicy(ctx context.Context, name string) (*CleanupPolicy, error) {
+	var result CleanupPolicy
+	if err := c.makeRequest(ctx, "GET", "/cleanup-policies/"+url.PathEscape(name), nil, &result); err != nil {
+		return nil, errors.Wrap(err, "CleanupPolicy")
+	}
+	return &result, nil
+}
+
+// CreateCleanupPolicy from the given criteria
+func (c Client) CreateCleanupPolicy(ctx context.Context, policy CleanupPolicy) error {
+	if err := c.writeCleanupPolicy(ctx, "POST", "/cleanup-policies", policy); err != nil {
+		return errors.Wrap(err, "CreateCleanupPolicy")
+	}
+	return nil
+}
+
+// UpdateCleanupPolicy replaces the criteria of an existing policy
+func (c Client) UpdateCleanupPolicy(ctx context.Context, policy CleanupPolicy) error {
+	if err := c.writeCleanupPolicy(ctx, "PUT", "/cleanup-policies/"+url.PathEscape(policy.Name), policy); err != nil {
+		return errors.Wrap(err, "UpdateCleanupPolicy")
+	}
+	return nil
+}
+
+// writeCleanupPolicy sends a policy after the common checks
+func (c Client) writeCleanupPolicy(ctx context.Context, method, endpoint string, policy CleanupPolicy) error {
+	if !c.authenticated() {
+		return errors.Wrap(ErrUnauthorized, "missing user authentication")
+	}
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	return c.makeJSONRequest(ctx, method, endpoint, nil, policy, nil)
+}
+
+// DeleteCleanupPolicy, repositories using it stop being cleaned up by it
+func (c Client) DeleteCleanupPolicy(ctx context.Context, name string) error {
+	if !c.authenticated() {
+		return errors.Wrap(ErrUnauthorized, "DeleteCleanupPolicy: missing user authentication")
+	}
+	if err := c.makeRequest(ctx, "DELETE", "/cleanup-policies/"+url.PathEscape(name), nil, nil); err != nil {
+		return errors.Wrap(err, "DeleteCleanupPolicy")
+	}
+	return nil
+}
+
+// AttachCleanupPolicy to a repository, attaching it twice is a no-op
+func (c Client) AttachCleanupPolicy(ctx context.Context, repositoryID, policyName string) error {
+	err := c.updateCleanupPolicies(ctx, repositoryID, func(names []string) []string {
+		for _, name := range names {
+			if name == policyName {
+				return names
+			}
+		}
+		return append(names, policyName)
+	})
+	return errors.Wrap(err, "AttachCleanupPolicy")
+}
+
+// DetachCleanupPolicy from a repository
+func (c Client) DetachCleanupPolicy(ctx context.Context, repositoryID, policyName string) error {
+	err := c.updateCleanupPolicies(ctx, repositoryID, func(names []string) []string {
+		kept := names[:0]
+		for _, name := range names {
+			if name != policyName {
+				kept = append(kept, name)
+			}
+		}
+		return kept
+	})
+	return errors.Wrap(err, "DetachCleanupPolicy")
+}
+
+// updateCleanupPolicies rewrites the repository's policy names with edit,
+// leaving the rest of its configuration as Nexus returned it
+func (c Client) updateCleanupPolicies(ctx context.Context, repositoryID string, edit func([]string) []string) error {
+	repo, err := c.repository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	doc, err := c.repositoryDocument(ctx, repo.Format, repo.Type, repo.Name)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	if cleanup, ok := doc["cleanup"].(map[string]interface{}); ok {
+		current, _ := cleanup["policyNames"].([]interface{})
+		for _, name := range current {
+			if name, ok := name.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	names = edit(names)
+	if names == nil {
+		names = []string{}
+	}
+	setDocumentPath(doc, "cleanup.policyNames", names)
+	return c.putRepositoryDocument(ctx, repo.Format, repo.Type, repo.Name, doc)
+}
+
+// PreviewCleanupPolicy lists the components of a repository the policy would
+// remove if it ran now. Nexus evaluates policies on the server, so this is an
+// approximation based on the asset metadata the API exposes.
+func (c Client) PreviewCleanupPolicy(ctx context.Context, policy CleanupPolicy, repositoryID string) ([]Component, error) {
+	if err := policy.validate(); err != nil {
+		return nil, errors.Wrap(err, "PreviewCleanupPolicy")
+	}
+	match, err := policy.matcher(time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "PreviewCleanupPolicy")
+	}
+
+	var result []Component
+	for cpnt, err := range c.AllComponents(ctx, repositoryID) {
+		if err != nil {
+			return nil, errors.Wrap(err, "PreviewCleanupPolicy")
+		}
+		if match(cpnt) {
+			result = append(result, cpnt)
+		}
+	}
+	return result, nil
+}
+
+func (p CleanupPolicy) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("missing cleanup policy name")
+	}
+	if p.Format == "" {
+		return fmt.Errorf("cleanup policy '%s' is missing a format", p.Name)
+	}
+	switch p.ReleaseType {
+	case "", ReleaseTypeReleases, ReleaseTypePrereleases:
+	default:
+		return fmt.Errorf("unknown release type '%s'", p.ReleaseType)
+	}
+	return nil
+}
+
+// matcher reports the components the policy would remove at the given time
+func (p CleanupPolicy) matcher(now time.Time) (func(Component) bool, error) {
+	var assetRegex *regexp.Regexp
+	if p.AssetRegex != "" {
+		var err error
+		if assetRegex, err = regexp.Compile(p.AssetRegex); err != nil {
+			return nil, errors.Wrap(err, "invalid asset regex")
+		}
+	}
+	olderThan := func(t time.Time, days int) bool {
+		return t.Before(now.AddDate(0, 0, -days))
+	}
+
+	return func(cpnt Component) bool {
+		if p.Format != CleanupFormatAll && p.Format != cpnt.Format {
+			return false
+		}
+		if p.ReleaseType != "" && (p.ReleaseType == ReleaseTypePrereleases) != prerelease(cpnt) {
+			return false
+		}
+
+		var updated, downloaded time.Time
+		pathMatched := assetRegex == nil
+		for _, asset := range cpnt.Assets {
+			blob := asset.BlobCreated
+			if blob.IsZero() {
+				blob = asset.LastModified
+			}
+			if blob.After(updated) {
+				updated = blob
+			}
+			if asset.LastDownloaded.After(downloaded) {
+				downloaded = asset.LastDownloaded
+			}
+			if assetRegex != nil && assetRegex.MatchString(strings.TrimPrefix(asset.Path, "/")) {
+				pathMatched = true
+			}
+		}
+		// Never downloaded components age from when they were stored
+		if downloaded.IsZero() {
+			downloaded = updated
+		}
+
+		if p.LastBlobUpdated != nil && !olderThan(updated, *p.LastBlobUpdated) {
+			return false
+		}
+		if p.LastDownloaded != nil && !olderThan(downloaded, *p.LastDownloaded) {
+			return false
+		}
+		return pathMatched
+	}, nil
+}
+
+var (
+	mavenSnapshot    = regexp.MustCompile(`-(SNAPSHOT|\d{8}\.\d{6}-\d+)$`)
+	pypiPrerelease   = regexp.MustCompile(`(?i)\d(a|b|rc|dev)\d*`)
+	semverPrerelease = regexp.MustCompile(`^v?\d+(\.\d+)*-.+`)
+)
+
+// prerelease reports if the component's version is a prerelease by the
+// conventions of its format. Formats without one, like apt and yum whose
+// versions carry a package revision after the dash, have no prereleases.
+func prerelease(cpnt Component) bool {
+	switch cpnt.Format {
+	case "maven2":
+		return mavenSnapshot.MatchString(cpnt.Version)
+	case "pypi":
+		return pypiPrerelease.MatchString(cpnt.Version)
+	case "npm", "nuget", "helm":
+		return semverPrerelease.MatchString(cpnt.Version)
+	}
+	return false
+}
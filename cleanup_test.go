@@ -0,0 +1,192 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestCleanupPolicyManagement(t *testing.T) {
+	var requests []string
+	var payload map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /cleanup-policies/old-snapshots":
+			w.Write([]byte(`{"name":"old-snapshots","format":"maven2","criteriaLastDownloaded":30,"criteriaReleaseType":"PRERELEASES"}`))
+		case "GET /repositories/maven-snapshots":
+			json.NewEncoder(w).Encode(Repository{Name: "maven-snapshots", Format: "maven2", Type: RepositoryTypeHosted})
+		case "GET /repositories/maven/hosted/maven-snapshots":
+			w.Write([]byte(`{"name":"maven-snapshots","format":"maven2","type":"hosted","online":true,
+				"routingRuleName":"snapshots-only","replication":{"preemptivePullEnabled":true},
+				"storage":{"blobStoreName":"default","writePolicy":"ALLOW"},
+				"cleanup":{"policyNames":["weekly"]},
+				"maven":{"versionPolicy":"SNAPSHOT","layoutPolicy":"STRICT"}}`))
+		case "GET /repositories/maven-central":
+			json.NewEncoder(w).Encode(Repository{Name: "maven-central", Format: "maven2", Type: RepositoryTypeProxy})
+		case "GET /repositories/maven/proxy/maven-central":
+			w.Write([]byte(`{"name":"maven-central","online":true,"storage":{"blobStoreName":"default"},
+				"proxy":{"remoteUrl":"https://repo1.maven.org/maven2/"},
+				"httpClient":{"authentication":{"type":"username","username":"mirror"}}}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"))
+	ctx := context.Background()
+
+	policy, err := c.CleanupPolicy(ctx, "old-snapshots")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.LastDownloaded == nil || *policy.LastDownloaded != 30 || policy.ReleaseType != ReleaseTypePrereleases {
+		t.Errorf("unexpected policy %+v", policy)
+	}
+
+	if err := c.CreateCleanupPolicy(ctx, *policy); err != nil {
+		t.Fatal(err)
+	}
+	if payload["criteriaLastDownloaded"] != 30.0 || payload["criteriaLastBlobUpdated"] != nil {
+		t.Errorf("unexpected payload %v", payload)
+	}
+	if err := c.UpdateCleanupPolicy(ctx, *policy); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.AttachCleanupPolicy(ctx, "maven-snapshots", "old-snapshots"); err != nil {
+		t.Fatal(err)
+	}
+	cleanup, _ := payload["cleanup"].(map[string]interface{})
+	if names, _ := json.Marshal(cleanup["policyNames"]); string(names) != `["weekly","old-snapshots"]` {
+		t.Errorf("unexpected policy names %s", names)
+	}
+	if payload["routingRule"] != "snapshots-only" || payload["replication"] == nil || payload["format"] != nil {
+		t.Errorf("unexpected repository configuration %v", payload)
+	}
+	if err := c.DetachCleanupPolicy(ctx, "maven-snapshots", "weekly"); err != nil {
+		t.Fatal(err)
+	}
+	cleanup, _ = payload["cleanup"].(map[string]interface{})
+	if names, _ := json.Marshal(cleanup["policyNames"]); string(names) != `[]` {
+		t.Errorf("unexpected policy names %s", names)
+	}
+
+	// The remote password isn't returned, so updating would clear it
+	if err := c.AttachCleanupPolicy(ctx, "maven-central", "old-snapshots"); err == nil {
+		t.Error("expected repository with remote credentials to be refused")
+	}
+
+	if err := c.DeleteCleanupPolicy(ctx, "old-snapshots"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "GET /cleanup-policies/old-snapshots,POST /cleanup-policies,PUT /cleanup-policies/old-snapshots," +
+		"GET /repositories/maven-snapshots,GET /repositories/maven/hosted/maven-snapshots,PUT /repositories/maven/hosted/maven-snapshots," +
+		"GET /repositories/maven-snapshots,GET /repositories/maven/hosted/maven-snapshots,PUT /repositories/maven/hosted/maven-snapshots," +
+		"GET /repositories/maven-central,GET /repositories/maven/proxy/maven-central,DELETE /cleanup-policies/old-snapshots"
+	if got := strings.Join(requests, ","); got != expected {
+		t.Errorf("unexpected requests %s", got)
+	}
+
+	if err := c.CreateCleanupPolicy(ctx, CleanupPolicy{Name: "invalid", Format: "raw", ReleaseType: "NIGHTLY"}); err == nil {
+		t.Error("expected unknown release type to be rejected")
+	}
+	anonymous, _ := New(ts.URL)
+	if err := anonymous.DeleteCleanupPolicy(ctx, "old-snapshots"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestCleanupPolicyMatcher(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	days := func(n int) *int { return &n }
+
+	component := func(format, version, path string, created, downloaded time.Time) Component {
+		return Component{Format: format, Name: "app", Version: version, Assets: []Asset{
+			{Path: path, BlobCreated: created, LastDownloaded: downloaded},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		policy   CleanupPolicy
+		cpnt     Component
+		expected bool
+	}{
+		{"stale snapshot", CleanupPolicy{Format: "maven2", LastDownloaded: days(30), ReleaseType: ReleaseTypePrereleases},
+			component("maven2", "1.0-20240101.120000-3", "com/app/1.0-SNAPSHOT/app.jar", daysAgo(90), daysAgo(60)), true},
+		{"recently downloaded", CleanupPolicy{Format: "maven2", LastDownloaded: days(30)},
+			component("maven2", "1.0-SNAPSHOT", "com/app/app.jar", daysAgo(90), daysAgo(2)), false},
+		{"never downloaded", CleanupPolicy{Format: CleanupFormatAll, LastDownloaded: days(30)},
+			component("npm", "1.0.0", "app/-/app-1.0.0.tgz", daysAgo(90), time.Time{}), true},
+		{"release excluded", CleanupPolicy{Format: "npm", ReleaseType: ReleaseTypePrereleases},
+			component("npm", "1.0.0", "app/-/app-1.0.0.tgz", daysAgo(90), time.Time{}), false},
+		{"pypi prerelease", CleanupPolicy{Format: "pypi", ReleaseType: ReleaseTypePrereleases},
+			component("pypi", "2.0rc1", "packages/app/2.0rc1/app-2.0rc1.tar.gz", daysAgo(1), time.Time{}), true},
+		{"recently updated", CleanupPolicy{Format: "raw", LastBlobUpdated: days(7)},
+			component("raw", "", "builds/app.zip", daysAgo(1), time.Time{}), false},
+		{"other format", CleanupPolicy{Format: "raw"},
+			component("npm", "1.0.0", "app/-/app-1.0.0.tgz", daysAgo(90), time.Time{}), false},
+		{"asset regex", CleanupPolicy{Format: "raw", AssetRegex: `^builds/.*\.zip$`},
+			component("raw", "", "/builds/app.zip", daysAgo(1), time.Time{}), true},
+		{"yum release", CleanupPolicy{Format: "yum", ReleaseType: ReleaseTypeReleases},
+			component("yum", "1.0-1.el8", "app-1.0-1.el8.x86_64.rpm", daysAgo(1), time.Time{}), true},
+		{"apt release", CleanupPolicy{Format: "apt", ReleaseType: ReleaseTypePrereleases},
+			component("apt", "1.2-1ubuntu1", "pool/a/app/app_1.2-1ubuntu1_amd64.deb", daysAgo(1), time.Time{}), false},
+		{"asset regex mismatch", CleanupPolicy{Format: "raw", AssetRegex: `^releases/`},
+			component("raw", "", "/builds/app.zip", daysAgo(1), time.Time{}), false},
+	}
+
+	for _, test := range tests {
+		match, err := test.policy.matcher(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := match(test.cpnt); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+
+	if _, err := (CleanupPolicy{AssetRegex: "("}).matcher(now); err == nil {
+		t.Error("expected invalid regex to be rejected")
+	}
+}
+
+func TestPreviewCleanupPolicy(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -90)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		items := []Component{
+			{ID: "old", Format: "raw", Assets: []Asset{{Path: "/old.zip", BlobCreated: old}}},
+			{ID: "new", Format: "raw", Assets: []Asset{{Path: "/new.zip", BlobCreated: time.Now()}}},
+		}
+		token := "next"
+		if r.URL.Query().Get("continuationToken") == "next" {
+			items, token = items[:1], ""
+			items[0].ID = "older"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items, "continuationToken": token})
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL)
+	days := 30
+	policy := CleanupPolicy{Name: "stale", Format: "raw", LastBlobUpdated: &days}
+	removed, err := c.PreviewCleanupPolicy(context.Background(), policy, "raw-hosted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 2 || removed[0].ID != "old" || removed[1].ID != "older" {
+		t.Errorf("unexpected preview %+v", removed)
+	}
+}
@@ -1,6 +1,11 @@
 package nexus
 
-import "github.com/pkg/errors"
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
 
 // Component object
 type Component struct {
@@ -13,42 +18,80 @@ type Component struct {
 	Assets     []Asset `json:"assets"`
 }
 
-// Components list
-func (c Client) Components() (components []Component, token string, err error) {
-	return nil, "", nil
+// Components list via endpoint
+func (c Client) Components(repositoryID, continuationToken string) (components []Component, token string, err error) {
+	return c.ComponentsContext(context.Background(), repositoryID, continuationToken)
+}
+
+// ComponentsContext list via endpoint
+func (c Client) ComponentsContext(ctx context.Context, repositoryID, continuationToken string) (components []Component, token string, err error) {
+	args := map[string]interface{}{
+		"repository":        repositoryID,
+		"continuationToken": continuationToken,
+	}
+
+	if continuationToken == "" {
+		delete(args, "continuationToken")
+	}
+
+	result := struct {
+		Items             []Component `json:"items"`
+		ContinuationToken string      `json:"continuationToken"`
+	}{}
+
+	err = c.makeRequest(ctx, "GET", "/components", args, &result)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Components")
+	}
+	return result.Items, result.ContinuationToken, nil
 }
 
 // UploadComponent to nexus
 func (c Client) UploadComponent(repositoryID string, parameters UploadParameters) (*Component, error) {
-	// Check Repo exists
-	repo, err := c.Repository(repositoryID)
+	return c.UploadComponentContext(context.Background(), repositoryID, parameters)
+}
+
+// UploadComponentContext to nexus
+func (c Client) UploadComponentContext(ctx context.Context, repositoryID string, parameters UploadParameters) (*Component, error) {
+	// Check Repo exists, only its format is needed
+	repo, err := c.repository(ctx, repositoryID)
 	if err != nil {
 		return nil, errors.Wrap(err, "UploadComponent")
 	}
 
-	switch repo.Format {
-	case "maven2":
-		return c.uploadMaven2Component(repositoryID, parameters)
-	case "raw":
-		return c.uploadRawComponent(repositoryID, parameters)
-	case "pypi":
-		return c.uploadPyPiComponent(repositoryID, parameters)
-	case "rubygems":
-		return c.uploadRubyGemsComponent(repositoryID, parameters)
-	case "nuget":
-		return c.uploadNugetComponent(repositoryID, parameters)
-	case "npm":
-		return c.uploadNPMComponent(repositoryID, parameters)
+	uploader, ok := c.uploader(repo.Format)
+	if !ok {
+		return nil, errors.Wrap(ErrUnknownRepoFormat, "UploadComponent")
 	}
-	return nil, errors.Wrap(ErrUnknownRepoFormat, "UploadComponent")
+	return uploader.Upload(ctx, c, repositoryID, parameters)
 }
 
 // Component single lookup
 func (c Client) Component(id string) (*Component, error) {
-	return nil, nil
+	return c.ComponentContext(context.Background(), id)
+}
+
+// ComponentContext single lookup
+func (c Client) ComponentContext(ctx context.Context, id string) (*Component, error) {
+	var result Component
+	if err := c.makeRequest(ctx, "GET", "/components/"+url.PathEscape(id), nil, &result); err != nil {
+		return nil, errors.Wrap(err, "Component")
+	}
+	return &result, nil
 }
 
 // DeleteComponent from nexus
 func (c Client) DeleteComponent(id string) error {
+	return c.DeleteComponentContext(context.Background(), id)
+}
+
+// DeleteComponentContext from nexus
+func (c Client) DeleteComponentContext(ctx context.Context, id string) error {
+	if !c.authenticated() {
+		return errors.Wrap(ErrUnauthorized, "DeleteComponent: missing user authentication")
+	}
+	if err := c.makeRequest(ctx, "DELETE", "/components/"+url.PathEscape(id), nil, nil); err != nil {
+		return errors.Wrap(err, "DeleteComponent")
+	}
 	return nil
 }
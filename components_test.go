@@ -1,12 +1,18 @@
 package nexus
 
 import (
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestComponents(t *testing.T) {
-	components, _, err := client.Components()
+	components, _, err := client.Components(testRepositoryID, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -64,14 +70,107 @@ func TestUploadRawComponent(t *testing.T) {
 	}
 }
 
-func TestUploadPyPiComponent(t *testing.T) { t.Skip("Not Implemented") }
+func TestComponent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/components/abc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"abc","repository":"maven-releases","format":"maven2","name":"test"}`))
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL)
+	component, err := c.Component("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || component.Name != "test" {
+		t.Errorf("unexpected component %+v", component)
+	}
 
-func TestUploadRubyGemComponent(t *testing.T) { t.Skip("Not Implemented") }
+	if _, err := c.Component("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
 
-func TestUploadNugetComponent(t *testing.T) { t.Skip("Not Implemented") }
+func TestDeleteComponent(t *testing.T) {
+	var method string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		if r.URL.Path != "/components/abc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	anonymous, _ := New(ts.URL)
+	if err := anonymous.DeleteComponent("abc"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
 
-func TestUploadNPMComponent(t *testing.T) { t.Skip("Not Implemented") }
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"))
+	if err := c.DeleteComponent("abc"); err != nil {
+		t.Fatal(err)
+	}
+	if method != "DELETE" {
+		t.Errorf("expected DELETE, got %s", method)
+	}
+	if err := c.DeleteComponent("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
 
-func TestComponent(t *testing.T) { t.Skip("Not Implemented") }
+// uploadServer fakes the upload and search endpoints, recording the fields
+// and file names of the last upload and the last search query. Searches only
+// find the result once something has been uploaded.
+type uploadServer struct {
+	*httptest.Server
+	fields map[string]string
+	files  map[string]string
+	search url.Values
+	// repositories listed by the server
+	repositories []Repository
+}
+
+func newUploadServer(t *testing.T, result Component) *uploadServer {
+	s := &uploadServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/components":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("invalid upload: %s", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			s.fields = map[string]string{}
+			for key, values := range r.MultipartForm.Value {
+				s.fields[key] = values[0]
+			}
+			s.files = map[string]string{}
+			for key, headers := range r.MultipartForm.File {
+				s.files[key] = headers[0].Filename
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && r.URL.Path == "/repositories":
+			json.NewEncoder(w).Encode(s.repositories)
+		case r.Method == "GET" && r.URL.Path == "/search":
+			s.search = r.URL.Query()
+			items := []Component{}
+			if s.files != nil {
+				items = append(items, result)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return s
+}
 
-func TestDeleteComponent(t *testing.T) { t.Skip("Not Implemented") }
+func (s *uploadServer) client() Client {
+	c, _ := New(s.URL, WithBasicAuth("admin", "admin123"))
+	return c
+}
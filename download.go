@@ -0,0 +1,217 @@
+package nexus
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrChecksumMismatch when downloaded content doesn't match the asset's checksum
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ChecksumMismatchError details which checksum failed to verify
+type ChecksumMismatchError struct {
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: expected %s, got %s", e.Algorithm, e.Expected, e.Actual)
+}
+
+// Is allows errors.Is(err, ErrChecksumMismatch)
+func (e *ChecksumMismatchError) Is(target error) bool { return target == ErrChecksumMismatch }
+
+// checksumAlgorithms in order of preference
+var checksumAlgorithms = []struct {
+	name string
+	new  func() hash.Hash
+}{
+	{"sha512", sha512.New},
+	{"sha256", sha256.New},
+	{"sha1", sha1.New},
+	{"md5", md5.New},
+}
+
+// checksumVerifier hashes everything written to it and compares the result
+// against the strongest checksum known for the asset
+type checksumVerifier struct {
+	hash.Hash
+	algorithm string
+	expected  string
+}
+
+// newChecksumVerifier returns nil when the asset has no known checksum
+func newChecksumVerifier(asset Asset) *checksumVerifier {
+	for _, a := range checksumAlgorithms {
+		if sum := asset.Checksum[a.name]; sum != "" {
+			return &checksumVerifier{Hash: a.new(), algorithm: a.name, expected: strings.ToLower(sum)}
+		}
+	}
+	return nil
+}
+
+func (v *checksumVerifier) verify() error {
+	actual := hex.EncodeToString(v.Sum(nil))
+	if actual != v.expected {
+		return &ChecksumMismatchError{Algorithm: v.algorithm, Expected: v.expected, Actual: actual}
+	}
+	return nil
+}
+
+// DownloadAsset streams the content of the asset into w, verifying it against
+// the asset's checksum when one is known. Credentials are only sent when the
+// download URL is on the client's Nexus host. Downloads aren't retried, the
+// content may already be partially written.
+func (c Client) DownloadAsset(ctx context.Context, asset Asset, w io.Writer) error {
+	res, err := c.download(ctx, asset, 0)
+	if err != nil {
+		return errors.Wrap(err, "DownloadAsset")
+	}
+	defer res.Body.Close()
+
+	verifier := newChecksumVerifier(asset)
+	if verifier != nil {
+		w = io.MultiWriter(w, verifier)
+	}
+
+	if _, err := io.Copy(w, res.Body); err != nil {
+		return errors.Wrap(err, "DownloadAsset")
+	}
+	if verifier != nil {
+		return errors.Wrap(verifier.verify(), "DownloadAsset")
+	}
+	return nil
+}
+
+// DownloadToFile downloads the asset to path. When path already holds part
+// of the asset the download resumes from where it stopped, provided the
+// server supports range requests, so calling it again retries a failed
+// download. A file failing checksum verification is left in place so the
+// caller can inspect it.
+func (c Client) DownloadToFile(ctx context.Context, asset Asset, path string) error {
+	var offset int64
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		offset = info.Size()
+	case !os.IsNotExist(err):
+		return errors.Wrap(err, "DownloadToFile")
+	}
+	if asset.FileSize > 0 && offset > asset.FileSize {
+		offset = 0
+	}
+
+	verifier := newChecksumVerifier(asset)
+
+	var res *http.Response
+	if asset.FileSize > 0 && offset == asset.FileSize {
+		// Already complete, just verify it
+		res = &http.Response{StatusCode: http.StatusPartialContent, Body: http.NoBody}
+	} else {
+		res, err = c.download(ctx, asset, offset)
+		if err != nil {
+			return errors.Wrap(err, "DownloadToFile")
+		}
+	}
+	defer res.Body.Close()
+
+	// Only created once the download is under way, so failed requests don't
+	// leave an empty file behind to resume from
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Wrap(err, "DownloadToFile")
+	}
+	defer file.Close()
+
+	if res.StatusCode != http.StatusPartialContent {
+		// The server sent everything, start over
+		offset = 0
+	}
+	if err := file.Truncate(offset); err != nil {
+		return errors.Wrap(err, "DownloadToFile")
+	}
+
+	if verifier != nil && offset > 0 {
+		if _, err := io.Copy(verifier, io.NewSectionReader(file, 0, offset)); err != nil {
+			return errors.Wrap(err, "DownloadToFile")
+		}
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return errors.Wrap(err, "DownloadToFile")
+	}
+
+	var w io.Writer = file
+	if verifier != nil {
+		w = io.MultiWriter(file, verifier)
+	}
+	if _, err := io.Copy(w, res.Body); err != nil {
+		return errors.Wrap(err, "DownloadToFile")
+	}
+	if verifier != nil {
+		return errors.Wrap(verifier.verify(), "DownloadToFile")
+	}
+	return nil
+}
+
+// download requests the asset content starting at offset, the caller must
+// close the response body. It bypasses the retry policy since the body is
+// streamed to the caller.
+func (c Client) download(ctx context.Context, asset Asset, offset int64) (*http.Response, error) {
+	if asset.DownloadURL == "" {
+		return nil, fmt.Errorf("asset '%s' has no download url", asset.ID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", asset.DownloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.sameHost(req.URL) {
+		c.prepare(req)
+	} else if c.userAgent != "" {
+		// Don't leak credentials or custom headers to whatever host the
+		// asset points at
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	c.traceRequest(req)
+	res, err := c.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// Stale partial file, fetch everything again
+		res.Body.Close()
+		return c.download(ctx, asset, 0)
+	case res.StatusCode < 200 || res.StatusCode > 299:
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxLoggedBody))
+		c.traceResponse(req, res, body, 0)
+		return nil, newAPIError(res, body)
+	}
+	return res, nil
+}
+
+// sameHost reports if u points at the Nexus instance the client talks to
+func (c Client) sameHost(u *url.URL) bool {
+	return strings.EqualFold(u.Scheme, c.uri.Scheme) && strings.EqualFold(u.Host, c.uri.Host)
+}
@@ -0,0 +1,124 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const testContent = "hello\ngo\n"
+
+func testDownloadServer(ranges *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ranges != nil {
+			*ranges = append(*ranges, r.Header.Get("Range"))
+		}
+		http.ServeContent(w, r, "test_asset.txt", time.Time{}, strings.NewReader(testContent))
+	}))
+}
+
+func testAsset(url string) Asset {
+	sum := sha256.Sum256([]byte(testContent))
+	return Asset{
+		ID:          "abc",
+		DownloadURL: url + "/repository/raw-repo/test_asset.txt",
+		FileSize:    int64(len(testContent)),
+		Checksum:    map[string]string{"sha256": hex.EncodeToString(sum[:])},
+	}
+}
+
+func TestDownloadAsset(t *testing.T) {
+	ts := testDownloadServer(nil)
+	defer ts.Close()
+	c, _ := New(ts.URL)
+
+	var buf bytes.Buffer
+	if err := c.DownloadAsset(context.Background(), testAsset(ts.URL), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != testContent {
+		t.Errorf("unexpected content %q", buf.String())
+	}
+
+	asset := testAsset(ts.URL)
+	asset.Checksum["sha256"] = "00"
+	err := c.DownloadAsset(context.Background(), asset, ioutil.Discard)
+	var mismatch *ChecksumMismatchError
+	if !errors.Is(err, ErrChecksumMismatch) || !errors.As(err, &mismatch) || mismatch.Algorithm != "sha256" {
+		t.Errorf("expected sha256 checksum mismatch, got %v", err)
+	}
+}
+
+func TestDownloadToFileResumes(t *testing.T) {
+	var ranges []string
+	ts := testDownloadServer(&ranges)
+	defer ts.Close()
+	c, _ := New(ts.URL)
+
+	path := filepath.Join(t.TempDir(), "test_asset.txt")
+	if err := ioutil.WriteFile(path, []byte(testContent[:4]), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.DownloadToFile(context.Background(), testAsset(ts.URL), path); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := ioutil.ReadFile(path)
+	if string(content) != testContent {
+		t.Errorf("unexpected content %q", content)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=4-" {
+		t.Errorf("expected a single range request, got %q", ranges)
+	}
+}
+
+func TestDownloadToFileError(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	c, _ := New(ts.URL)
+
+	path := filepath.Join(t.TempDir(), "test_asset.txt")
+	if err := c.DownloadToFile(context.Background(), testAsset(ts.URL), path); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no file to be left behind, got %v", err)
+	}
+}
+
+func TestDownloadCredentials(t *testing.T) {
+	var auth []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization")+"|"+r.Header.Get("X-Custom"))
+		http.ServeContent(w, r, "test_asset.txt", time.Time{}, strings.NewReader(testContent))
+	})
+	nexus := httptest.NewServer(handler)
+	defer nexus.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+
+	c, _ := New(nexus.URL+"/service/rest/v1", WithBasicAuth("admin", "admin123"), WithHeader("X-Custom", "secret"))
+	for _, url := range []string{nexus.URL, other.URL} {
+		if err := c.DownloadAsset(context.Background(), testAsset(url), ioutil.Discard); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if auth[0] == "|" || auth[0] == "" {
+		t.Errorf("expected credentials for the nexus host, got %q", auth[0])
+	}
+	if auth[1] != "|" {
+		t.Errorf("expected no credentials for another host, got %q", auth[1])
+	}
+}
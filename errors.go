@@ -0,0 +1,89 @@
+package nexus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when Nexus responds with a non successful status code
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	// Message reported by Nexus, if any could be extracted from the body
+	Message string
+	// Body of the response as returned by Nexus
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is maps the status code onto the package's sentinel errors, so
+// errors.Is(err, ErrNotFound) works for a 404 response
+func (e *APIError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusConflict:
+		return target == ErrConflict
+	}
+	return false
+}
+
+// newAPIError builds an APIError from a failed response and its body
+func newAPIError(res *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: res.StatusCode,
+		Body:       body,
+	}
+	if res.Request != nil {
+		e.Method = res.Request.Method
+		e.URL = res.Request.URL.String()
+	}
+	e.Message = parseErrorMessage(body)
+	return e
+}
+
+// parseErrorMessage extracts a readable message from a Nexus error body,
+// which is either a list of validation errors, a single error object or text
+func parseErrorMessage(body []byte) string {
+	type validationError struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+
+	var list []validationError
+	if err := json.Unmarshal(body, &list); err == nil {
+		msgs := make([]string, 0, len(list))
+		for _, v := range list {
+			if v.Message != "" {
+				msgs = append(msgs, v.Message)
+			}
+		}
+		return strings.Join(msgs, "; ")
+	}
+
+	var single validationError
+	if err := json.Unmarshal(body, &single); err == nil {
+		return single.Message
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if strings.HasPrefix(msg, "<") {
+		// HTML error pages aren't worth repeating
+		return ""
+	}
+	return msg
+}
@@ -0,0 +1,44 @@
+package nexus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestAPIErrorStatusMapping(t *testing.T) {
+	tests := []struct {
+		status int
+		target error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusConflict, ErrConflict},
+	}
+
+	for _, tt := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(`[{"id":"*","message":"something went wrong"}]`))
+		}))
+
+		c, _ := New(ts.URL)
+		err := c.Ping()
+		ts.Close()
+
+		if !errors.Is(err, tt.target) {
+			t.Errorf("%d: expected %v, got %v", tt.status, tt.target, err)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%d: expected *APIError, got %T", tt.status, err)
+		}
+		if apiErr.Method != "GET" || apiErr.Message != "something went wrong" {
+			t.Errorf("%d: unexpected error details %+v", tt.status, apiErr)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package nexus
+
+import (
+	"net/http"
+	"time"
+)
+
+// maxLoggedBody limits how much of a response body is traced
+const maxLoggedBody = 1024
+
+// Logger receives the client's log output. It is satisfied by *slog.Logger,
+// nothing is logged unless one is configured with WithLogger.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// nopLogger discards everything
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+// log returns the configured Logger, or one that discards everything
+func (c Client) log() Logger {
+	if c.logger == nil {
+		return nopLogger{}
+	}
+	return c.logger
+}
+
+// sensitiveHeaders are never logged as is
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactHeaders returns a copy of h with credentials masked
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, key := range sensitiveHeaders {
+		if out.Get(key) != "" {
+			out.Set(key, "REDACTED")
+		}
+	}
+	return out
+}
+
+// traceRequest logs an outgoing request at debug level
+func (c Client) traceRequest(req *http.Request) {
+	c.log().Debug("nexus request",
+		"method", req.Method,
+		"url", req.URL.Redacted(),
+		"headers", redactHeaders(req.Header),
+	)
+}
+
+// traceResponse logs a received response at debug level, failures at warn
+func (c Client) traceResponse(req *http.Request, res *http.Response, body []byte, elapsed time.Duration) {
+	if len(body) > maxLoggedBody {
+		body = body[:maxLoggedBody]
+	}
+
+	args := []any{
+		"method", req.Method,
+		"url", req.URL.Redacted(),
+		"status", res.StatusCode,
+		"elapsed", elapsed,
+		"body", string(body),
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		c.log().Warn("nexus request failed", args...)
+		return
+	}
+	c.log().Debug("nexus response", args...)
+}
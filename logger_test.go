@@ -0,0 +1,33 @@
+package nexus
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoggerRedactsCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"), WithLogger(logger))
+	if err := c.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "nexus request") {
+		t.Fatalf("expected request to be traced, got %q", out)
+	}
+	if !strings.Contains(out, "REDACTED") || strings.Contains(out, "Basic ") {
+		t.Errorf("expected credentials to be redacted, got %q", out)
+	}
+}
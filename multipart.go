@@ -0,0 +1,279 @@
+package nexus
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// UploadProgress is notified as an upload is sent, allowing progress bars
+// to be rendered. Calls are made from the goroutine encoding the request.
+type UploadProgress interface {
+	// AssetStarted when the content of the form field starts being sent,
+	// size is -1 when unknown
+	AssetStarted(field, filename string, size int64)
+	// AssetFinished when the content of the form field has been sent or
+	// failed to be read
+	AssetFinished(field, filename string, err error)
+	// Sent reports the bytes of the request sent so far and its total size,
+	// which is -1 when unknown
+	Sent(sent, total int64)
+}
+
+// ProgressFunc adapts a function to an UploadProgress only interested in
+// the number of bytes sent
+type ProgressFunc func(sent, total int64)
+
+// AssetStarted does nothing
+func (f ProgressFunc) AssetStarted(field, filename string, size int64) {}
+
+// AssetFinished does nothing
+func (f ProgressFunc) AssetFinished(field, filename string, err error) {}
+
+// Sent calls f
+func (f ProgressFunc) Sent(sent, total int64) { f(sent, total) }
+
+// UploadSource supplies the content of an uploaded asset
+type UploadSource struct {
+	// Filename sent to Nexus for the asset
+	Filename string
+	// Size of the content in bytes, -1 when unknown
+	Size int64
+
+	open       func() (io.ReadCloser, error)
+	replayable bool
+	// check reports why the content can't be read, before anything is sent
+	check func() error
+}
+
+// FileSource reads the asset from a file on disk
+func FileSource(path string) UploadSource {
+	size := int64(-1)
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	return UploadSource{
+		Filename:   filepath.Base(path),
+		Size:       size,
+		open:       func() (io.ReadCloser, error) { return os.Open(path) },
+		replayable: true,
+		check: func() error {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			return f.Close()
+		},
+	}
+}
+
+// ReaderSource reads the asset from r, size may be -1 when unknown. Unless r
+// is also an io.Seeker it can only be read once, so the upload won't be retried.
+func ReaderSource(filename string, r io.Reader, size int64) UploadSource {
+	src := UploadSource{Filename: filename, Size: size}
+
+	if seeker, ok := r.(io.Seeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			src.replayable = true
+			src.open = func() (io.ReadCloser, error) {
+				if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+					return nil, err
+				}
+				return ioutil.NopCloser(r), nil
+			}
+			return src
+		}
+	}
+
+	var once sync.Once
+	src.open = func() (io.ReadCloser, error) {
+		used := true
+		once.Do(func() { used = false })
+		if used {
+			return nil, errSourceConsumed
+		}
+		return ioutil.NopCloser(r), nil
+	}
+	return src
+}
+
+// formPart is either a plain field or a file
+type formPart struct {
+	name   string
+	value  string
+	source *UploadSource
+}
+
+// multipartForm describes a multipart/form-data body which is only encoded
+// as it is sent, so file contents are never held in memory
+type multipartForm struct {
+	boundary string
+	parts    []formPart
+}
+
+func newMultipartForm() *multipartForm {
+	return &multipartForm{boundary: multipart.NewWriter(ioutil.Discard).Boundary()}
+}
+
+// WriteField adds a plain field to the form
+func (f *multipartForm) WriteField(name, value string) {
+	f.parts = append(f.parts, formPart{name: name, value: value})
+}
+
+// AddFile adds a file part to the form
+func (f *multipartForm) AddFile(name string, source UploadSource) {
+	f.parts = append(f.parts, formPart{name: name, source: &source})
+}
+
+// FormDataContentType for the request header
+func (f *multipartForm) FormDataContentType() string {
+	return "multipart/form-data; boundary=" + f.boundary
+}
+
+// validate checks every file in the form can be read, so a missing file
+// fails before a truncated body is sent
+func (f *multipartForm) validate() error {
+	for _, p := range f.parts {
+		if p.source != nil && p.source.check != nil {
+			if err := p.source.check(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// replayable reports if every file in the form can be read again
+func (f *multipartForm) replayable() bool {
+	for _, p := range f.parts {
+		if p.source != nil && !p.source.replayable {
+			return false
+		}
+	}
+	return true
+}
+
+// size of the encoded form, -1 when any file size is unknown
+func (f *multipartForm) size() int64 {
+	var files int64
+	for _, p := range f.parts {
+		if p.source != nil {
+			if p.source.Size < 0 {
+				return -1
+			}
+			files += p.source.Size
+		}
+	}
+
+	counter := &countingWriter{w: ioutil.Discard}
+	if err := f.encode(context.Background(), counter, false, nil); err != nil {
+		return -1
+	}
+	return counter.n + files
+}
+
+// encode writes the form to w, leaving out file contents unless withFiles.
+// It stops as soon as ctx is done.
+func (f *multipartForm) encode(ctx context.Context, w io.Writer, withFiles bool, progress UploadProgress) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(f.boundary); err != nil {
+		return err
+	}
+
+	for _, p := range f.parts {
+		if p.source == nil {
+			if err := writer.WriteField(p.name, p.value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="`+escapeQuotes(p.name)+`"; filename="`+escapeQuotes(p.source.Filename)+`"`)
+		h.Set("Content-Type", "application/octet-stream")
+		part, err := writer.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if !withFiles {
+			continue
+		}
+
+		if progress != nil {
+			progress.AssetStarted(p.name, p.source.Filename, p.source.Size)
+		}
+		err = copySource(ctx, part, p.source)
+		if progress != nil {
+			progress.AssetFinished(p.name, p.source.Filename, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+func copySource(ctx context.Context, w io.Writer, source *UploadSource) error {
+	r, err := source.open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(w, contextReader{ctx: ctx, r: r})
+	return err
+}
+
+// contextReader fails reads once ctx is done, aborting long copies
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// reader streams the encoded form through a pipe, reporting progress if set
+func (f *multipartForm) reader(ctx context.Context, progress UploadProgress) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	var w io.Writer = pw
+	if progress != nil {
+		total := f.size()
+		w = &countingWriter{w: pw, fn: func(n int64) { progress.Sent(n, total) }}
+	}
+
+	go func() {
+		pw.CloseWithError(f.encode(ctx, w, true, progress))
+	}()
+	return pr
+}
+
+// countingWriter counts the bytes passing through it
+type countingWriter struct {
+	w  io.Writer
+	n  int64
+	fn func(n int64)
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if c.fn != nil && n > 0 {
+		c.fn(c.n)
+	}
+	return n, err
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string { return quoteEscaper.Replace(s) }
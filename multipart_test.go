@@ -0,0 +1,135 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestMultipartFormSize(t *testing.T) {
+	form := newMultipartForm()
+	form.AddFile("raw.asset1", ReaderSource("test_asset.txt", strings.NewReader(testContent), int64(len(testContent))))
+	form.WriteField("raw.asset1.filename", "test_asset.txt")
+	form.WriteField("raw.directory", "/com/example/test")
+
+	body, err := ioutil.ReadAll(form.reader(context.Background(), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := form.size(); size != int64(len(body)) {
+		t.Errorf("expected size %d, got %d", len(body), size)
+	}
+	if !form.replayable() {
+		t.Error("seekable readers should be replayable")
+	}
+}
+
+func TestMultipartStreamsReaders(t *testing.T) {
+	var got []byte
+	var contentLength int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		file, _, err := r.FormFile("raw.asset1")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got, _ = ioutil.ReadAll(file)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"))
+
+	// A plain io.Reader, neither seekable nor of known size
+	content := bytes.Repeat([]byte(testContent), 1000)
+	form := newMultipartForm()
+	form.AddFile("raw.asset1", ReaderSource("test_asset.txt", ioutil.NopCloser(bytes.NewReader(content)), -1))
+
+	var sent, total int64
+	progress := ProgressFunc(func(s, t int64) { sent, total = s, t })
+	if err := c.makeMultiPartRequest(context.Background(), "POST", "/components", nil, form, progress, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, content) {
+		t.Errorf("server received %d bytes, expected %d", len(got), len(content))
+	}
+	if contentLength != -1 || total != -1 || sent <= int64(len(content)) {
+		t.Errorf("unexpected length %d and progress %d/%d", contentLength, sent, total)
+	}
+}
+
+type testProgress struct {
+	events []string
+	sent   int64
+}
+
+func (p *testProgress) AssetStarted(field, filename string, size int64) {
+	p.events = append(p.events, "start "+field)
+}
+
+func (p *testProgress) AssetFinished(field, filename string, err error) {
+	p.events = append(p.events, "finish "+field)
+}
+
+func (p *testProgress) Sent(sent, total int64) { p.sent = sent }
+
+func TestMultipartProgress(t *testing.T) {
+	form := newMultipartForm()
+	form.AddFile("maven2.asset1", ReaderSource("test.jar", strings.NewReader(testContent), int64(len(testContent))))
+	form.AddFile("maven2.asset2", ReaderSource("test.pom", strings.NewReader(testContent), int64(len(testContent))))
+
+	progress := &testProgress{}
+	body, err := ioutil.ReadAll(form.reader(context.Background(), progress))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "start maven2.asset1,finish maven2.asset1,start maven2.asset2,finish maven2.asset2"
+	if got := strings.Join(progress.events, ","); got != expected {
+		t.Errorf("unexpected events %s", got)
+	}
+	if progress.sent != int64(len(body)) {
+		t.Errorf("expected %d bytes sent, got %d", len(body), progress.sent)
+	}
+}
+
+func TestMultipartCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	form := newMultipartForm()
+	form.AddFile("raw.asset1", ReaderSource("test_asset.txt", strings.NewReader(testContent), -1))
+	if _, err := ioutil.ReadAll(form.reader(ctx, nil)); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMultipartMissingFile(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"))
+	_, err := c.UploadRaw(context.Background(), "raw-repo", RawUpload{
+		Directory: "test",
+		Assets:    []RawAsset{{Source: FileSource("does/not/exist.txt")}},
+	})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the missing file to be reported, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected nothing to be sent, got %d requests", calls)
+	}
+}
@@ -1,11 +1,15 @@
+// Package nexus is a client for the Nexus Repository Manager 3 REST API.
+//
+// Methods predating context support come in X and XContext pairs, every
+// other method takes a context.Context as its first argument.
 package nexus
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"io"
 	"net/http"
 	"net/url"
 	"time"
@@ -20,33 +24,83 @@ var (
 	ErrUnknownRepoFormat = errors.New("can't handle unknown repo format")
 	// ErrMissingFiles when we expect files but don't find any
 	ErrMissingFiles = errors.New("expecting files, but none were found")
+	// ErrUnauthorized when the credentials are missing or rejected
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden when the user isn't allowed to perform the request
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict when the request conflicts with existing state
+	ErrConflict = errors.New("conflict")
+
+	errUnsupportedTransport = errors.New("transport options require an *http.Transport")
+	errSourceConsumed       = errors.New("upload source can only be read once")
 )
 
+// defaultTimeout applied to regular API requests when none has been configured
+const defaultTimeout = time.Second * 5
+
 // Client hander for making REST API calls
 type Client struct {
-	uri      *url.URL
-	username string
-	password string
+	uri            *url.URL
+	username       string
+	password       string
+	token          string
+	headers        http.Header
+	userAgent      string
+	httpClient     *http.Client
+	timeout        time.Duration
+	uploadTimeout  time.Duration
+	logger         Logger
+	retry          RetryPolicy
+	uploaders      map[string]Uploader
+	resolveTimeout time.Duration
 }
 
-// New Client handler
-func New(nexusRestURL string) (Client, error) {
+// New Client handler, configured by the given options
+func New(nexusRestURL string, options ...Option) (Client, error) {
 	u, err := url.Parse(nexusRestURL)
 	if err != nil {
 		return Client{}, err
 	}
 
-	return Client{
-		uri: u,
-	}, nil
+	c := Client{
+		uri:            u,
+		timeout:        defaultTimeout,
+		retry:          DefaultRetryPolicy,
+		resolveTimeout: defaultResolveTimeout,
+	}
+	for _, option := range options {
+		if err := option(&c); err != nil {
+			return Client{}, errors.Wrap(err, "New")
+		}
+	}
+	return c, nil
 }
 
+// SetBasicAuth returns a copy of the client using the given credentials
+//
+// Deprecated: use the WithBasicAuth option with New
 func (c Client) SetBasicAuth(username, password string) Client {
-	return Client{
-		uri:      c.uri,
-		username: username,
-		password: password,
+	_ = WithBasicAuth(username, password)(&c)
+	return c
+}
+
+// SetHTTPClient returns a copy of the client sending requests through the
+// given http.Client, allowing connection pools to be shared
+func (c Client) SetHTTPClient(httpClient *http.Client) Client {
+	c.httpClient = httpClient
+	return c
+}
+
+// SetTransport returns a copy of the client sending requests through the
+// given http.RoundTripper
+func (c Client) SetTransport(transport http.RoundTripper) Client {
+	hc := &http.Client{}
+	if c.httpClient != nil {
+		*hc = *c.httpClient
 	}
+	hc.Transport = transport
+	c.httpClient = hc
+	return c
 }
 
 // Address returns the address string
@@ -56,10 +110,61 @@ func (c Client) url() string {
 	return fmt.Sprintf("%s://%s%s", c.uri.Scheme, c.uri.Host, c.uri.Path)
 }
 
-func (c Client) makeRequest(method, endpoint string, args map[string]interface{}, result interface{}) error {
+// client returns the http.Client requests are sent through
+func (c Client) client() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return http.DefaultClient
+}
+
+// prepare applies authentication and the configured headers to req
+func (c Client) prepare(req *http.Request) {
+	for key, values := range c.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+}
+
+// authenticated reports if the client has any credentials configured
+func (c Client) authenticated() bool { return c.username != "" || c.token != "" }
+
+func (c Client) makeRequest(ctx context.Context, method, endpoint string, args map[string]interface{}, result interface{}) error {
+	return c.makeJSONRequest(ctx, method, endpoint, args, nil, result)
+}
+
+// makeJSONRequest is makeRequest sending payload, when not nil, as a JSON body
+func (c Client) makeJSONRequest(ctx context.Context, method, endpoint string, args map[string]interface{}, payload, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
 	url := c.url() + endpoint
-	req, _ := http.NewRequest(method, url, nil)
-	req.Header.Add("Accept", "application/json")
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	c.prepare(req)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	q := req.URL.Query()
 	for key, value := range args {
@@ -67,44 +172,44 @@ func (c Client) makeRequest(method, endpoint string, args map[string]interface{}
 	}
 	req.URL.RawQuery = q.Encode()
 
-	httpClient := http.Client{
-		Timeout: time.Second * 5,
-	}
-	res, err := httpClient.Do(req)
+	res, rbody, err := c.do(req, c.timeout)
 	if err != nil {
 		return err
 	}
-	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return err
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return newAPIError(res, rbody)
 	}
-
-	// fmt.Printf("makeRequest: URL     (%s): %s\n", endpoint, req.URL.String())
-	// fmt.Printf("makeRequest: Status  (%s): %s\n", endpoint, res.Status)
-	// fmt.Printf("makeRequest: Headers (%s): %s\n", endpoint, req.Header.Get("Content-Type"))
-	// fmt.Printf("makeRequest: Body    (%s): %s\n", endpoint, body)
-
-	fmt.Printf("Client:makeRequest -> [%s] --> [%s] \n%s\n\n", endpoint, args, body)
-	return json.Unmarshal(body, result)
+	if result == nil || len(rbody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(rbody, result)
 }
 
-func (c Client) makeMultiPartRequest(method, endpoint string, args map[string]interface{}, headers map[string]string, body *bytes.Buffer, result interface{}) error {
-	if c.username == "" {
+func (c Client) makeMultiPartRequest(ctx context.Context, method, endpoint string, args map[string]interface{}, form *multipartForm, progress UploadProgress, result interface{}) error {
+	if !c.authenticated() {
 		return fmt.Errorf("missing user authentication for upload")
 	}
+	if err := form.validate(); err != nil {
+		return errors.Wrap(err, "makeMultiPartRequest")
+	}
 
 	url := c.url() + endpoint
-	req, _ := http.NewRequest(method, url, body)
-	req.Header.Set("Accept", "application/json")
-
-	req.SetBasicAuth(c.username, c.password)
-
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	body := form.reader(ctx, progress)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		body.Close()
+		return errors.Wrap(err, "makeMultiPartRequest")
 	}
-	// req.Header.Set("Content-Type", "multipart/form-data")
+	if size := form.size(); size >= 0 {
+		req.ContentLength = size
+	}
+	if form.replayable() {
+		req.GetBody = func() (io.ReadCloser, error) { return form.reader(ctx, progress), nil }
+	}
+	c.prepare(req)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", form.FormDataContentType())
 
 	q := req.URL.Query()
 	for key, value := range args {
@@ -112,24 +217,15 @@ func (c Client) makeMultiPartRequest(method, endpoint string, args map[string]in
 	}
 	req.URL.RawQuery = q.Encode()
 
-	// log.Printf("url: %s", req.URL)
-	// log.Printf("message: %s", body)
-
-	httpClient := http.Client{}
-	res, err := httpClient.Do(req)
+	res, rbody, err := c.do(req, c.uploadTimeout)
 	if err != nil {
 		return errors.Wrap(err, "makeMultiPartRequest")
 	}
-	defer res.Body.Close()
-	log.Printf("Upload resp: %#v\n", res)
 
-	rbody, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return errors.Wrap(err, "makeMultiPartRequest")
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return newAPIError(res, rbody)
 	}
-
-	// log.Printf("response: %s", rbody)
-	if result == nil {
+	if result == nil || len(rbody) == 0 {
 		return nil
 	}
 	return json.Unmarshal(rbody, result)
@@ -137,6 +233,11 @@ func (c Client) makeMultiPartRequest(method, endpoint string, args map[string]in
 
 // Ping is used to test we can connect to the service
 func (c Client) Ping() error {
+	return c.PingContext(context.Background())
+}
+
+// PingContext is used to test we can connect to the service
+func (c Client) PingContext(ctx context.Context) error {
 	var result map[string]interface{}
-	return c.makeRequest("GET", "/read-only", nil, &result)
+	return c.makeRequest(ctx, "GET", "/read-only", nil, &result)
 }
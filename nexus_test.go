@@ -1,6 +1,15 @@
 package nexus
 
-var client = New("http://localhost:8081/service/rest/v1").SetBasicAuth("admin", "admin123")
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+var client, _ = New("http://localhost:8081/service/rest/v1", WithBasicAuth("admin", "admin123"))
 
 // // Comment this out to run tests agaist an existing instance
 // func TestMain(m *testing.M) {
@@ -19,7 +28,7 @@ var client = New("http://localhost:8081/service/rest/v1").SetBasicAuth("admin",
 // 	// Exponential backoff-retry, because the application in the container might not be ready to accept connections yet
 // 	err = pool.Retry(func() error {
 // 		//
-// 		client = New(fmt.Sprintf("localhost:%s/service/rest/v1", resource.GetPort("8081/tcp"))).SetBasicAuth("admin", "admin123")
+// 		client, _ = New(fmt.Sprintf("localhost:%s/service/rest/v1", resource.GetPort("8081/tcp")), WithBasicAuth("admin", "admin123"))
 // 		return client.Ping()
 // 	})
 // 	if err != nil {
@@ -35,3 +44,21 @@ var client = New("http://localhost:8081/service/rest/v1").SetBasicAuth("admin",
 
 // 	os.Exit(code)
 // }
+
+func TestPingContextCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL)
+	if err := c.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.PingContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package nexus
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Client when it is created with New
+type Option func(*Client) error
+
+// WithBasicAuth authenticates every request with the given username and password
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) error {
+		c.username = username
+		c.password = password
+		c.token = ""
+		return nil
+	}
+}
+
+// WithUserToken authenticates every request with a Nexus user token, made up
+// of the token name code and pass code
+func WithUserToken(nameCode, passCode string) Option {
+	return WithBasicAuth(nameCode, passCode)
+}
+
+// WithBearerToken authenticates every request with an Authorization: Bearer header
+func WithBearerToken(token string) Option {
+	return func(c *Client) error {
+		c.token = token
+		c.username = ""
+		c.password = ""
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request
+func WithHeader(key, value string) Option {
+	return func(c *Client) error {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		c.headers.Add(key, value)
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithTimeout limits how long regular API requests may take, zero disables the limit
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithUploadTimeout limits how long uploads may take, zero disables the limit
+func WithUploadTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.uploadTimeout = timeout
+		return nil
+	}
+}
+
+// WithHTTPClient sends requests through the given http.Client, allowing
+// connection pools to be shared
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		c.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTransport sends requests through the given http.RoundTripper
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) error {
+		*c = c.SetTransport(transport)
+		return nil
+	}
+}
+
+// WithTLSConfig uses the given TLS configuration when connecting to Nexus
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) error {
+		return c.configureTransport(func(t *http.Transport) {
+			t.TLSClientConfig = config
+		})
+	}
+}
+
+// WithProxy sends every request through the given proxy URL
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return err
+		}
+		return c.configureTransport(func(t *http.Transport) {
+			t.Proxy = http.ProxyURL(u)
+		})
+	}
+}
+
+// WithLogger sends the client's log output to the given Logger
+func WithLogger(logger Logger) Option {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// configureTransport applies fn to a copy of the client's *http.Transport,
+// falling back to a copy of http.DefaultTransport when none is set
+func (c *Client) configureTransport(fn func(*http.Transport)) error {
+	var transport *http.Transport
+	if c.httpClient != nil && c.httpClient.Transport != nil {
+		t, ok := c.httpClient.Transport.(*http.Transport)
+		if !ok {
+			return errUnsupportedTransport
+		}
+		transport = t.Clone()
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+
+	fn(transport)
+	*c = c.SetTransport(transport)
+	return nil
+}
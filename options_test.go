@@ -0,0 +1,49 @@
+package nexus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOptionsApplyHeaders(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL,
+		WithBearerToken("s3cr3t"),
+		WithHeader("X-Team", "build"),
+		WithUserAgent("go-nexus-test"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := got.Get("Authorization"); v != "Bearer s3cr3t" {
+		t.Errorf("Authorization = %q", v)
+	}
+	if v := got.Get("X-Team"); v != "build" {
+		t.Errorf("X-Team = %q", v)
+	}
+	if v := got.Get("User-Agent"); v != "go-nexus-test" {
+		t.Errorf("User-Agent = %q", v)
+	}
+}
+
+func TestOptionsProxyRejectsCustomTransport(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(nil)}
+	if _, err := New("http://localhost", WithHTTPClient(hc), WithProxy("http://proxy:3128")); err == nil {
+		t.Fatal("expected error configuring proxy on a custom transport")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,161 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// PageFunc fetches the page identified by token, returning its items and the
+// token for the next page, which is empty on the last page
+type PageFunc[T any] func(ctx context.Context, token string) ([]T, string, error)
+
+// Iterator walks every item of a paged endpoint, following the
+// continuationToken between pages as needed
+//
+//	it := client.AssetsIterator(ctx, "maven-releases")
+//	for it.Next() {
+//		asset := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch PageFunc[T]
+	limit int
+
+	page    []T
+	item    T
+	token   string
+	started bool
+	done    bool
+	count   int
+	err     error
+}
+
+// NewIterator over the pages returned by fetch
+func NewIterator[T any](ctx context.Context, fetch PageFunc[T]) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch}
+}
+
+// Limit stops the iteration after n items, zero means no limit
+func (it *Iterator[T]) Limit(n int) *Iterator[T] {
+	it.limit = n
+	return it
+}
+
+// Next advances to the next item, fetching the next page when required.
+// It returns false at the end of the results or on error.
+func (it *Iterator[T]) Next() bool {
+	if it.done || (it.limit > 0 && it.count >= it.limit) {
+		it.done = true
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if it.started && it.token == "" {
+			it.done = true
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+
+		page, token, err := it.fetch(it.ctx, it.token)
+		if err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+		if token != "" && token == it.token {
+			// The same page would be fetched forever
+			it.err = fmt.Errorf("continuation token '%s' didn't advance", token)
+			it.done = true
+			return false
+		}
+		it.started = true
+		it.page, it.token = page, token
+	}
+
+	it.item, it.page = it.page[0], it.page[1:]
+	it.count++
+	return true
+}
+
+// Item returns the current item
+func (it *Iterator[T]) Item() T { return it.item }
+
+// Token returns the continuation token of the next page still to be fetched
+func (it *Iterator[T]) Token() string { return it.token }
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error { return it.err }
+
+// All returns a range-over-func sequence of the remaining items. An error
+// stops the sequence after being yielded with the zero value.
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.Item(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// AssetsIterator walks every asset in a repository
+func (c Client) AssetsIterator(ctx context.Context, repositoryID string) *Iterator[Asset] {
+	return NewIterator(ctx, func(ctx context.Context, token string) ([]Asset, string, error) {
+		return c.AssetsContext(ctx, repositoryID, token)
+	})
+}
+
+// ComponentsIterator walks every component in a repository
+func (c Client) ComponentsIterator(ctx context.Context, repositoryID string) *Iterator[Component] {
+	return NewIterator(ctx, func(ctx context.Context, token string) ([]Component, string, error) {
+		return c.ComponentsContext(ctx, repositoryID, token)
+	})
+}
+
+// SearchComponentsIterator walks every component matching the parameters
+func (c Client) SearchComponentsIterator(ctx context.Context, parameters SearchParameters) *Iterator[Component] {
+	return NewIterator(ctx, func(ctx context.Context, token string) ([]Component, string, error) {
+		parameters.ContinuationToken = token
+		return c.SearchComponentsContext(ctx, parameters)
+	})
+}
+
+// SearchAssetsIterator walks every asset matching the parameters
+func (c Client) SearchAssetsIterator(ctx context.Context, parameters SearchParameters) *Iterator[Asset] {
+	return NewIterator(ctx, func(ctx context.Context, token string) ([]Asset, string, error) {
+		parameters.ContinuationToken = token
+		return c.SearchAssetsContext(ctx, parameters)
+	})
+}
+
+// AllAssets in a repository, as a range-over-func sequence
+func (c Client) AllAssets(ctx context.Context, repositoryID string) iter.Seq2[Asset, error] {
+	return c.AssetsIterator(ctx, repositoryID).All()
+}
+
+// AllComponents in a repository, as a range-over-func sequence
+func (c Client) AllComponents(ctx context.Context, repositoryID string) iter.Seq2[Component, error] {
+	return c.ComponentsIterator(ctx, repositoryID).All()
+}
+
+// AllSearchComponents matching the parameters, as a range-over-func sequence
+func (c Client) AllSearchComponents(ctx context.Context, parameters SearchParameters) iter.Seq2[Component, error] {
+	return c.SearchComponentsIterator(ctx, parameters).All()
+}
+
+// AllSearchAssets matching the parameters, as a range-over-func sequence
+func (c Client) AllSearchAssets(ctx context.Context, parameters SearchParameters) iter.Seq2[Asset, error] {
+	return c.SearchAssetsIterator(ctx, parameters).All()
+}
@@ -0,0 +1,94 @@
+package nexus
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// pages of three items, four pages long
+func testPages(calls *int) PageFunc[int] {
+	return func(ctx context.Context, token string) ([]int, string, error) {
+		*calls++
+		n, _ := strconv.Atoi(token)
+		next := ""
+		if n < 9 {
+			next = strconv.Itoa(n + 3)
+		}
+		return []int{n, n + 1, n + 2}, next, nil
+	}
+}
+
+func TestIteratorFollowsTokens(t *testing.T) {
+	calls := 0
+	it := NewIterator(context.Background(), testPages(&calls))
+
+	var got []int
+	for it.Next() {
+		got = append(got, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 12 || got[11] != 11 || calls != 4 {
+		t.Errorf("unexpected results %v after %d calls", got, calls)
+	}
+}
+
+func TestIteratorLimit(t *testing.T) {
+	calls := 0
+	it := NewIterator(context.Background(), testPages(&calls)).Limit(4)
+
+	count := 0
+	for range it.All() {
+		count++
+	}
+	if count != 4 || calls != 2 {
+		t.Errorf("expected 4 items from 2 pages, got %d items from %d pages", count, calls)
+	}
+}
+
+func TestIteratorEarlyTermination(t *testing.T) {
+	calls := 0
+	for v, err := range NewIterator(context.Background(), testPages(&calls)).All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v == 1 {
+			break
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected a single page to be fetched, got %d", calls)
+	}
+}
+
+func TestIteratorCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	it := NewIterator(ctx, testPages(&calls))
+	if it.Next() {
+		t.Fatal("expected no items")
+	}
+	if !errors.Is(it.Err(), context.Canceled) || calls != 0 {
+		t.Errorf("expected context.Canceled without fetching, got %v after %d calls", it.Err(), calls)
+	}
+}
+
+func TestIteratorStuckToken(t *testing.T) {
+	calls := 0
+	it := NewIterator(context.Background(), func(ctx context.Context, token string) ([]int, string, error) {
+		calls++
+		return nil, "stuck", nil
+	})
+	if it.Next() {
+		t.Fatal("expected no items")
+	}
+	if it.Err() == nil || calls != 2 {
+		t.Errorf("expected an error after the token repeated, got %v after %d calls", it.Err(), calls)
+	}
+}
@@ -0,0 +1,283 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ChangeAction taken on a repository by a plan
+type ChangeAction string
+
+// Actions of a RepositoryChange
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+	ChangeDelete ChangeAction = "delete"
+)
+
+// FieldChange is a single differing attribute, Path uses the JSON names,
+// e.g. "storage.writePolicy"
+type FieldChange struct {
+	Path    string
+	Current interface{}
+	Desired interface{}
+}
+
+// RepositoryChange to bring a repository to its desired state
+type RepositoryChange struct {
+	Action ChangeAction
+	Name   string
+	// Desired configuration, nil for deletes
+	Desired *RepositoryConfig
+	// Fields that differ, only set for updates
+	Fields []FieldChange
+
+	// format and type of the repository and the configuration to PUT, the
+	// current one with the desired attributes applied
+	format, repositoryType string
+	document               map[string]interface{}
+}
+
+// RepositoryPlan lists the changes reconciling the server with the desired
+// repositories, ordered so group members exist before their groups
+type RepositoryPlan struct {
+	Changes []RepositoryChange
+}
+
+// Empty reports if the server already matches the desired state
+func (p RepositoryPlan) Empty() bool { return len(p.Changes) == 0 }
+
+// String renders the plan as a human readable diff
+func (p RepositoryPlan) String() string {
+	if p.Empty() {
+		return "no changes\n"
+	}
+
+	var b strings.Builder
+	for _, change := range p.Changes {
+		switch change.Action {
+		case ChangeCreate:
+			fmt.Fprintf(&b, "+ create %s (%s %s)\n", change.Name, change.Desired.Format, change.Desired.Type)
+		case ChangeUpdate:
+			fmt.Fprintf(&b, "~ update %s\n", change.Name)
+			for _, f := range change.Fields {
+				fmt.Fprintf(&b, "    %s: %s => %s\n", f.Path, diffValue(f.Path, f.Current), diffValue(f.Path, f.Desired))
+			}
+		case ChangeDelete:
+			fmt.Fprintf(&b, "- delete %s\n", change.Name)
+		}
+	}
+	return b.String()
+}
+
+func diffValue(path string, v interface{}) string {
+	if v == nil {
+		return "(unset)"
+	}
+	if secretPaths[path] {
+		return "(redacted)"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// ReconcileOptions control how repositories are reconciled
+type ReconcileOptions struct {
+	// Prune deletes repositories that aren't in the desired set
+	Prune bool
+	// DryRun only plans the changes, nothing is applied
+	DryRun bool
+}
+
+// PlanRepositories compares the desired repositories against the server.
+// Configurations decoded from JSON, or YAML with a decoder honouring JSON
+// field names like sigs.k8s.io/yaml, only manage the attributes present in
+// the document, plus any set in code afterwards, the others keep their
+// current value. Configurations built in code are compared in full. Secrets
+// Nexus doesn't return are never compared, but are sent along with updates.
+func (c Client) PlanRepositories(ctx context.Context, desired []RepositoryConfig, prune bool) (*RepositoryPlan, error) {
+	current, err := c.RepositoriesContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "PlanRepositories")
+	}
+	existing := make(map[string]Repository, len(current))
+	for _, repo := range current {
+		existing[repo.Name] = repo
+	}
+
+	var creates, updates, deletes []RepositoryChange
+	wanted := make(map[string]bool, len(desired))
+	for i := range desired {
+		want := desired[i]
+		if wanted[want.Name] {
+			return nil, fmt.Errorf("PlanRepositories: repository '%s' is defined more than once", want.Name)
+		}
+		wanted[want.Name] = true
+
+		if _, err := want.endpoint(); err != nil {
+			return nil, errors.Wrap(err, "PlanRepositories")
+		}
+
+		repo, ok := existing[want.Name]
+		if !ok {
+			creates = append(creates, RepositoryChange{Action: ChangeCreate, Name: want.Name, Desired: &want})
+			continue
+		}
+		// The list reports maven2 where maven is accepted as well
+		if repositoryAPIFormats[repo.Format] != repositoryAPIFormats[want.Format] || repo.Type != want.Type {
+			return nil, fmt.Errorf("PlanRepositories: repository '%s' is %s %s, it can't be changed to %s %s",
+				want.Name, repo.Format, repo.Type, want.Format, want.Type)
+		}
+
+		current, err := c.repositoryDocument(ctx, repo.Format, repo.Type, repo.Name)
+		if err != nil {
+			return nil, errors.Wrap(err, "PlanRepositories")
+		}
+		fields, document, err := diffRepositoryConfig(current, want)
+		if err != nil {
+			return nil, errors.Wrap(err, "PlanRepositories")
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		if missing := missingSecrets(document); len(missing) > 0 {
+			return nil, fmt.Errorf("PlanRepositories: repository '%s' needs %s, which Nexus doesn't return, to be updated",
+				want.Name, strings.Join(missing, " and "))
+		}
+		updates = append(updates, RepositoryChange{
+			Action:         ChangeUpdate,
+			Name:           want.Name,
+			Desired:        &want,
+			Fields:         fields,
+			format:         repo.Format,
+			repositoryType: repo.Type,
+			document:       document,
+		})
+	}
+
+	if prune {
+		for _, repo := range current {
+			if !wanted[repo.Name] {
+				deletes = append(deletes, RepositoryChange{Action: ChangeDelete, Name: repo.Name})
+			}
+		}
+	}
+
+	// Groups are created after, and deleted before, the repositories they hold
+	isGroup := func(change RepositoryChange) bool {
+		if change.Desired != nil {
+			return change.Desired.Type == RepositoryTypeGroup
+		}
+		return existing[change.Name].Type == RepositoryTypeGroup
+	}
+	sort.SliceStable(creates, func(i, j int) bool { return !isGroup(creates[i]) && isGroup(creates[j]) })
+	sort.SliceStable(deletes, func(i, j int) bool { return isGroup(deletes[i]) && !isGroup(deletes[j]) })
+
+	plan := &RepositoryPlan{}
+	plan.Changes = append(plan.Changes, creates...)
+	plan.Changes = append(plan.Changes, updates...)
+	plan.Changes = append(plan.Changes, deletes...)
+	return plan, nil
+}
+
+// ApplyRepositoryPlan makes the planned changes, stopping at the first failure
+func (c Client) ApplyRepositoryPlan(ctx context.Context, plan *RepositoryPlan) error {
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Action {
+		case ChangeCreate:
+			err = c.CreateRepository(ctx, *change.Desired)
+		case ChangeUpdate:
+			if change.document == nil {
+				err = c.UpdateRepository(ctx, *change.Desired)
+				break
+			}
+			err = c.putRepositoryDocument(ctx, change.format, change.repositoryType, change.Name, change.document)
+		case ChangeDelete:
+			err = c.DeleteRepository(ctx, change.Name)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "ApplyRepositoryPlan: %s %s", change.Action, change.Name)
+		}
+	}
+	return nil
+}
+
+// ReconcileRepositories plans and, unless running dry, applies the changes
+// bringing the server to the desired repositories. The plan is returned in
+// either case.
+func (c Client) ReconcileRepositories(ctx context.Context, desired []RepositoryConfig, options ReconcileOptions) (*RepositoryPlan, error) {
+	plan, err := c.PlanRepositories(ctx, desired, options.Prune)
+	if err != nil || options.DryRun {
+		return plan, err
+	}
+	return plan, c.ApplyRepositoryPlan(ctx, plan)
+}
+
+// diffRepositoryConfig lists the managed attributes of desired that differ
+// from the current document, along with the document to update it with
+func diffRepositoryConfig(current map[string]interface{}, desired RepositoryConfig) ([]FieldChange, map[string]interface{}, error) {
+	want, err := configDocument(desired.payload())
+	if err != nil {
+		return nil, nil, err
+	}
+	have := flattenDocument(current)
+	wanted := flattenDocument(want)
+
+	var fields []FieldChange
+	for path, value := range wanted {
+		if !desired.manages(path, value) {
+			continue
+		}
+		setDocumentPath(current, path, value)
+		// Secrets are sent along but can't be compared
+		if !secretPaths[path] && !reflect.DeepEqual(have[path], value) {
+			fields = append(fields, FieldChange{Path: path, Current: have[path], Desired: value})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields, current, nil
+}
+
+// manages reports if the attribute at path is part of the desired state:
+// decoded configurations only manage what the document or later code set
+func (r RepositoryConfig) manages(path string, value interface{}) bool {
+	if secretPaths[path] {
+		return value != nil && value != ""
+	}
+	if r.present == nil || r.present[path] {
+		return true
+	}
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// configDocument encodes the configuration as a decoded JSON document
+func configDocument(config RepositoryConfig) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
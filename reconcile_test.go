@@ -0,0 +1,186 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReconcileRepositories(t *testing.T) {
+	var changes []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repositories":
+			json.NewEncoder(w).Encode([]Repository{
+				{Name: "raw-hosted", Format: "raw", Type: RepositoryTypeHosted},
+				{Name: "raw-group", Format: "raw", Type: RepositoryTypeGroup},
+				{Name: "raw-old", Format: "raw", Type: RepositoryTypeHosted},
+			})
+		case r.Method == "GET" && r.URL.Path == "/repositories/raw/hosted/raw-hosted":
+			json.NewEncoder(w).Encode(RepositoryConfig{
+				Name:    "raw-hosted",
+				Online:  true,
+				Storage: &StorageAttributes{BlobStoreName: "default", StrictContentTypeValidation: true, WritePolicy: WritePolicyAllow},
+			})
+		case r.Method == "GET" && r.URL.Path == "/repositories/raw/group/raw-group":
+			json.NewEncoder(w).Encode(RepositoryConfig{
+				Name:    "raw-group",
+				Online:  true,
+				Storage: &StorageAttributes{BlobStoreName: "default", StrictContentTypeValidation: true},
+				Group:   &GroupAttributes{MemberNames: []string{"raw-hosted"}},
+			})
+		case r.Method == "GET":
+			http.NotFound(w, r)
+		default:
+			changes = append(changes, r.Method+" "+r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"))
+	ctx := context.Background()
+
+	storage := func(policy string) *StorageAttributes {
+		return &StorageAttributes{BlobStoreName: "default", StrictContentTypeValidation: true, WritePolicy: policy}
+	}
+	desired := []RepositoryConfig{
+		{
+			Name: "raw-group", Format: "raw", Type: RepositoryTypeGroup, Online: true,
+			Storage: &StorageAttributes{BlobStoreName: "default", StrictContentTypeValidation: true},
+			Group:   &GroupAttributes{MemberNames: []string{"raw-hosted", "raw-new"}},
+		},
+		{Name: "raw-hosted", Format: "raw", Type: RepositoryTypeHosted, Online: true, Storage: storage(WritePolicyAllowOnce)},
+		{Name: "raw-new", Format: "raw", Type: RepositoryTypeHosted, Online: true, Storage: storage(WritePolicyAllow)},
+	}
+
+	plan, err := c.ReconcileRepositories(ctx, desired, ReconcileOptions{Prune: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("dry run made changes %v", changes)
+	}
+	expected := `+ create raw-new (raw hosted)
+~ update raw-group
+    group.memberNames: ["raw-hosted"] => ["raw-hosted","raw-new"]
+~ update raw-hosted
+    storage.writePolicy: "ALLOW" => "ALLOW_ONCE"
+- delete raw-old
+`
+	if got := plan.String(); got != expected {
+		t.Errorf("unexpected plan\n%s", got)
+	}
+
+	if err := c.ApplyRepositoryPlan(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+	expected = "POST /repositories/raw/hosted,PUT /repositories/raw/group/raw-group,PUT /repositories/raw/hosted/raw-hosted,DELETE /repositories/raw-old"
+	if got := strings.Join(changes, ","); got != expected {
+		t.Errorf("unexpected changes %s", got)
+	}
+
+	// Without pruning, repositories missing from the desired set are kept
+	plan, err = c.PlanRepositories(ctx, desired[:1], false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, change := range plan.Changes {
+		if change.Action == ChangeDelete {
+			t.Errorf("unexpected delete of %s", change.Name)
+		}
+	}
+
+	// Changing a repository's type can't be done in place
+	desired[1].Type = RepositoryTypeProxy
+	if _, err := c.PlanRepositories(ctx, desired[1:2], false); err == nil {
+		t.Error("expected type change to be rejected")
+	}
+}
+
+func TestRepositoryPlanEmpty(t *testing.T) {
+	plan := RepositoryPlan{}
+	if !plan.Empty() || plan.String() != "no changes\n" {
+		t.Errorf("unexpected empty plan %q", plan.String())
+	}
+}
+
+func TestReconcilePartialConfig(t *testing.T) {
+	var put map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repositories":
+			w.Write([]byte(`[{"name":"maven-central","format":"maven2","type":"proxy"}]`))
+		case "GET /repositories/maven/proxy/maven-central":
+			w.Write([]byte(`{"name":"maven-central","format":"maven2","type":"proxy","url":"http://localhost/repository/maven-central",
+				"online":true,"routingRuleName":"allow-apache",
+				"storage":{"blobStoreName":"default","strictContentTypeValidation":true},
+				"proxy":{"remoteUrl":"https://repo1.maven.org/maven2/","contentMaxAge":-1,"metadataMaxAge":1440},
+				"negativeCache":{"enabled":true,"timeToLive":1440},
+				"httpClient":{"blocked":false,"autoBlock":true,"authentication":{"type":"username","username":"mirror"}},
+				"maven":{"versionPolicy":"RELEASE","layoutPolicy":"STRICT"},
+				"replication":{"preemptivePullEnabled":true}}`))
+		case "PUT /repositories/maven/proxy/maven-central":
+			json.NewDecoder(r.Body).Decode(&put)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"))
+	ctx := context.Background()
+
+	// Only the remote and the credentials are managed, online is left alone
+	var desired RepositoryConfig
+	err := json.Unmarshal([]byte(`{"name":"maven-central","format":"maven","type":"proxy",
+		"storage":{"blobStoreName":"default"},
+		"proxy":{"remoteUrl":"https://repo.maven.apache.org/maven2/"},
+		"negativeCache":{},"httpClient":{"authentication":{"type":"username","username":"mirror"}},
+		"maven":{"versionPolicy":"RELEASE","layoutPolicy":"STRICT"}}`), &desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Updating would clear the remote password, which Nexus doesn't return
+	if _, err := c.PlanRepositories(ctx, []RepositoryConfig{desired}, false); err == nil ||
+		!strings.Contains(err.Error(), "httpClient.authentication.password") {
+		t.Fatalf("expected missing password to be rejected, got %v", err)
+	}
+
+	desired.HTTPClient.Authentication.Password = "s3cr3t"
+	plan, err := c.ReconcileRepositories(ctx, []RepositoryConfig{desired}, ReconcileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `~ update maven-central
+    proxy.remoteUrl: "https://repo1.maven.org/maven2/" => "https://repo.maven.apache.org/maven2/"
+`
+	if got := plan.String(); got != expected {
+		t.Errorf("unexpected plan\n%s", got)
+	}
+
+	fields := flattenDocument(put)
+	if fields["online"] != true || fields["proxy.contentMaxAge"] != -1.0 || fields["httpClient.autoBlock"] != true {
+		t.Errorf("unmanaged attributes changed %v", put)
+	}
+	if fields["routingRule"] != "allow-apache" || fields["replication.preemptivePullEnabled"] != true {
+		t.Errorf("attributes RepositoryConfig doesn't model were dropped %v", put)
+	}
+	if fields["httpClient.authentication.password"] != "s3cr3t" || fields["proxy.remoteUrl"] != "https://repo.maven.apache.org/maven2/" {
+		t.Errorf("desired attributes weren't sent %v", put)
+	}
+	if _, ok := put["format"]; ok {
+		t.Errorf("read only attributes were sent %v", put)
+	}
+
+	// Secrets never show in the diff
+	plan.Changes[0].Fields = append(plan.Changes[0].Fields, FieldChange{Path: "aptSigning.passphrase", Desired: "hunter2"})
+	if strings.Contains(plan.String(), "hunter2") {
+		t.Errorf("secret leaked into plan\n%s", plan.String())
+	}
+}
@@ -1,17 +1,74 @@
 package nexus
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 // Repository object
 type Repository struct {
 	Name   string `json:"name"`
 	Format string `json:"format"`
 	Type   string `json:"type"`
 	URL    string `json:"url"`
+	// Config holds the full configuration, it is only filled in by
+	// Repository lookups and is nil when the server can't provide it
+	Config *RepositoryConfig `json:"-"`
+}
+
+// Online reports if the repository is serving requests, repositories
+// without a known configuration are assumed to be
+func (r Repository) Online() bool {
+	return r.Config == nil || r.Config.Online
+}
+
+// BlobStoreName the repository stores its content in
+func (r Repository) BlobStoreName() string {
+	if r.Config == nil || r.Config.Storage == nil {
+		return ""
+	}
+	return r.Config.Storage.BlobStoreName
+}
+
+// WritePolicy of a hosted repository
+func (r Repository) WritePolicy() string {
+	if r.Config == nil || r.Config.Storage == nil {
+		return ""
+	}
+	return r.Config.Storage.WritePolicy
+}
+
+// RemoteURL a proxy repository fetches content from
+func (r Repository) RemoteURL() string {
+	if r.Config == nil || r.Config.Proxy == nil {
+		return ""
+	}
+	return r.Config.Proxy.RemoteURL
+}
+
+// Members of a group repository
+func (r Repository) Members() []string {
+	if r.Config == nil || r.Config.Group == nil {
+		return nil
+	}
+	return r.Config.Group.MemberNames
 }
 
 // Repositories list
 func (c Client) Repositories() ([]Repository, error) {
+	return c.RepositoriesContext(context.Background())
+}
+
+// RepositoriesContext list
+func (c Client) RepositoriesContext(ctx context.Context) ([]Repository, error) {
 	var result []Repository
-	err := c.makeRequest("GET", "/repositories", nil, &result)
+	err := c.makeRequest(ctx, "GET", "/repositories", nil, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -20,7 +77,47 @@ func (c Client) Repositories() ([]Repository, error) {
 
 // Repository lookup
 func (c Client) Repository(repositoryID string) (*Repository, error) {
-	repos, err := c.Repositories()
+	return c.RepositoryContext(context.Background(), repositoryID)
+}
+
+// RepositoryContext lookup, including the repository's full configuration
+// when the user is allowed to read it
+func (c Client) RepositoryContext(ctx context.Context, repositoryID string) (*Repository, error) {
+	repo, err := c.repository(ctx, repositoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Repository")
+	}
+
+	repo.Config, err = c.RepositoryConfig(ctx, repo.Format, repo.Type, repo.Name)
+	if unsupportedEndpoint(err) {
+		// Older servers may still offer the settings of every repository
+		repo.Config, err = c.repositorySettings(ctx, repositoryID)
+	}
+	if err != nil && !configUnavailable(err) {
+		return nil, errors.Wrap(err, "Repository")
+	}
+	return repo, nil
+}
+
+// repository looks up the name, format and type of a repository, which any
+// user allowed to browse it can read, without its configuration
+func (c Client) repository(ctx context.Context, repositoryID string) (*Repository, error) {
+	var repo Repository
+	err := c.makeRequest(ctx, "GET", "/repositories/"+url.PathEscape(repositoryID), nil, &repo)
+	if unsupportedEndpoint(err) {
+		// Older servers can only list repositories, which also tells apart
+		// repositories that don't exist
+		return c.repositoryFromList(ctx, repositoryID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// repositoryFromList looks up the repository by scanning the full list
+func (c Client) repositoryFromList(ctx context.Context, repositoryID string) (*Repository, error) {
+	repos, err := c.RepositoriesContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -32,3 +129,340 @@ func (c Client) Repository(repositoryID string) (*Repository, error) {
 	}
 	return nil, ErrNotFound
 }
+
+// RepositoryConfig fetches the full configuration of a repository, which
+// requires permission to administer it
+func (c Client) RepositoryConfig(ctx context.Context, format, repositoryType, name string) (*RepositoryConfig, error) {
+	apiFormat, ok := repositoryAPIFormats[format]
+	if !ok {
+		apiFormat = format
+	}
+
+	var config RepositoryConfig
+	endpoint := fmt.Sprintf("/repositories/%s/%s/%s", apiFormat, repositoryType, url.PathEscape(name))
+	if err := c.makeRequest(ctx, "GET", endpoint, nil, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// RepositorySettings lists the full configuration of every repository
+func (c Client) RepositorySettings(ctx context.Context) ([]RepositoryConfig, error) {
+	var result []RepositoryConfig
+	if err := c.makeRequest(ctx, "GET", "/repositorySettings", nil, &result); err != nil {
+		return nil, errors.Wrap(err, "RepositorySettings")
+	}
+	return result, nil
+}
+
+// repositorySettings finds the configuration of a single repository in the
+// settings list, which servers without per repository endpoints may offer
+func (c Client) repositorySettings(ctx context.Context, name string) (*RepositoryConfig, error) {
+	configs, err := c.RepositorySettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		if config.Name == name {
+			return &config, nil
+		}
+	}
+	return nil, nil
+}
+
+// unsupportedEndpoint reports errors caused by the server not knowing an
+// endpoint, which older versions of Nexus lack
+func unsupportedEndpoint(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed
+}
+
+// configUnavailable reports errors leaving a repository's configuration
+// unknown, either because the server lacks the endpoint or because the user,
+// e.g. one only allowed to deploy, may not read it
+func configUnavailable(err error) bool {
+	return unsupportedEndpoint(err) || errors.Is(err, ErrForbidden) || errors.Is(err, ErrUnauthorized)
+}
+
+// RepositoryConfig describes a repository to create or update. Only the
+// attributes relevant to its format and type need to be set.
+type RepositoryConfig struct {
+	Name string `json:"name"`
+	// Format of the repository, e.g. maven2, npm or docker
+	Format string `json:"format,omitempty"`
+	// Type is one of RepositoryTypeHosted, RepositoryTypeProxy or RepositoryTypeGroup
+	Type   string `json:"type,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Online bool   `json:"online"`
+
+	Storage       *StorageAttributes       `json:"storage,omitempty"`
+	Cleanup       *CleanupAttributes       `json:"cleanup,omitempty"`
+	Component     *ComponentAttributes     `json:"component,omitempty"`
+	Proxy         *ProxyAttributes         `json:"proxy,omitempty"`
+	NegativeCache *NegativeCacheAttributes `json:"negativeCache,omitempty"`
+	HTTPClient    *HTTPClientAttributes    `json:"httpClient,omitempty"`
+	RoutingRule   string                   `json:"routingRule,omitempty"`
+	Group         *GroupAttributes         `json:"group,omitempty"`
+
+	Maven       *MavenAttributes       `json:"maven,omitempty"`
+	Raw         *RawAttributes         `json:"raw,omitempty"`
+	Docker      *DockerAttributes      `json:"docker,omitempty"`
+	DockerProxy *DockerProxyAttributes `json:"dockerProxy,omitempty"`
+	Apt         *AptAttributes         `json:"apt,omitempty"`
+	AptSigning  *AptSigningAttributes  `json:"aptSigning,omitempty"`
+	Yum         *YumAttributes         `json:"yum,omitempty"`
+	NugetProxy  *NugetProxyAttributes  `json:"nugetProxy,omitempty"`
+
+	// present holds the attribute paths found in the decoded document, nil
+	// for configurations built in code
+	present map[string]bool
+}
+
+// UnmarshalJSON records which attributes the document sets, so reconciling
+// a partial configuration leaves the others alone
+func (r *RepositoryConfig) UnmarshalJSON(data []byte) error {
+	type plain RepositoryConfig
+	var config plain
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	*r = RepositoryConfig(config)
+	r.present = map[string]bool{}
+	for path := range flattenDocument(doc) {
+		r.present[path] = true
+	}
+	// Nexus reports the routing rule under a different name than it accepts
+	if name, ok := doc["routingRuleName"].(string); ok && r.RoutingRule == "" {
+		r.RoutingRule = name
+		r.present["routingRule"] = true
+	}
+	return nil
+}
+
+// repositoryAPIFormats maps repository formats onto the name used by the
+// repository management endpoints
+var repositoryAPIFormats = map[string]string{
+	"maven2": "maven",
+	"maven":  "maven",
+	"raw":    "raw",
+	"npm":    "npm",
+	"pypi":   "pypi",
+	"nuget":  "nuget",
+	"docker": "docker",
+	"helm":   "helm",
+	"apt":    "apt",
+	"yum":    "yum",
+}
+
+// endpoint for the repository's format and type, validating the attributes
+// the type requires are present
+func (r RepositoryConfig) endpoint() (string, error) {
+	if r.Name == "" {
+		return "", fmt.Errorf("missing repository name")
+	}
+	endpoint, err := repositoryEndpoint(r.Format, r.Type)
+	if err != nil {
+		return "", err
+	}
+	format := repositoryAPIFormats[r.Format]
+
+	switch r.Type {
+	case RepositoryTypeHosted:
+		if r.Storage == nil {
+			return "", fmt.Errorf("hosted repository '%s' is missing storage", r.Name)
+		}
+	case RepositoryTypeProxy:
+		if r.Storage == nil || r.Proxy == nil || r.NegativeCache == nil || r.HTTPClient == nil {
+			return "", fmt.Errorf("proxy repository '%s' needs storage, proxy, negative cache and http client attributes", r.Name)
+		}
+	case RepositoryTypeGroup:
+		if r.Storage == nil || r.Group == nil {
+			return "", fmt.Errorf("group repository '%s' needs storage and group attributes", r.Name)
+		}
+	}
+
+	// Format specific attributes Nexus insists on
+	missing := ""
+	switch {
+	case format == "maven" && r.Type != RepositoryTypeGroup && r.Maven == nil:
+		missing = "maven"
+	case format == "docker" && r.Docker == nil:
+		missing = "docker"
+	case format == "docker" && r.Type == RepositoryTypeProxy && r.DockerProxy == nil:
+		missing = "docker proxy"
+	case format == "apt" && r.Type != RepositoryTypeGroup && r.Apt == nil:
+		missing = "apt"
+	case format == "apt" && r.Type == RepositoryTypeHosted && r.AptSigning == nil:
+		missing = "apt signing"
+	case format == "yum" && r.Type == RepositoryTypeHosted && r.Yum == nil:
+		missing = "yum"
+	case format == "nuget" && r.Type == RepositoryTypeProxy && r.NugetProxy == nil:
+		missing = "nuget proxy"
+	}
+	if missing != "" {
+		return "", fmt.Errorf("%s %s repository '%s' is missing %s attributes", r.Format, r.Type, r.Name, missing)
+	}
+	return endpoint, nil
+}
+
+// repositoryEndpoint managing repositories of the given format and type
+func repositoryEndpoint(format, repositoryType string) (string, error) {
+	apiFormat, ok := repositoryAPIFormats[format]
+	if !ok {
+		return "", errors.Wrapf(ErrUnknownRepoFormat, "'%s'", format)
+	}
+	switch repositoryType {
+	case RepositoryTypeHosted, RepositoryTypeProxy, RepositoryTypeGroup:
+	default:
+		return "", fmt.Errorf("unknown repository type '%s'", repositoryType)
+	}
+	return fmt.Sprintf("/repositories/%s/%s", apiFormat, repositoryType), nil
+}
+
+// payload sent to Nexus, which derives the format and type from the endpoint
+func (r RepositoryConfig) payload() RepositoryConfig {
+	r.Format, r.Type, r.URL = "", "", ""
+	return r
+}
+
+// CreateRepository from the given configuration
+func (c Client) CreateRepository(ctx context.Context, config RepositoryConfig) error {
+	if !c.authenticated() {
+		return errors.Wrap(ErrUnauthorized, "CreateRepository: missing user authentication")
+	}
+	endpoint, err := config.endpoint()
+	if err != nil {
+		return errors.Wrap(err, "CreateRepository")
+	}
+	if err := c.makeJSONRequest(ctx, "POST", endpoint, nil, config.payload(), nil); err != nil {
+		return errors.Wrap(err, "CreateRepository")
+	}
+	return nil
+}
+
+// UpdateRepository replaces the configuration of an existing repository
+func (c Client) UpdateRepository(ctx context.Context, config RepositoryConfig) error {
+	if !c.authenticated() {
+		return errors.Wrap(ErrUnauthorized, "UpdateRepository: missing user authentication")
+	}
+	endpoint, err := config.endpoint()
+	if err != nil {
+		return errors.Wrap(err, "UpdateRepository")
+	}
+	endpoint += "/" + url.PathEscape(config.Name)
+	if err := c.makeJSONRequest(ctx, "PUT", endpoint, nil, config.payload(), nil); err != nil {
+		return errors.Wrap(err, "UpdateRepository")
+	}
+	return nil
+}
+
+// DeleteRepository and everything stored in it
+func (c Client) DeleteRepository(ctx context.Context, name string) error {
+	if !c.authenticated() {
+		return errors.Wrap(ErrUnauthorized, "DeleteRepository: missing user authentication")
+	}
+	if err := c.makeRequest(ctx, "DELETE", "/repositories/"+url.PathEscape(name), nil, nil); err != nil {
+		return errors.Wrap(err, "DeleteRepository")
+	}
+	return nil
+}
+
+// repositoryDocument fetches the configuration of a repository as returned
+// by Nexus, keeping the attributes RepositoryConfig doesn't model
+func (c Client) repositoryDocument(ctx context.Context, format, repositoryType, name string) (map[string]interface{}, error) {
+	endpoint, err := repositoryEndpoint(format, repositoryType)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := c.makeRequest(ctx, "GET", endpoint+"/"+url.PathEscape(name), nil, &doc); err != nil {
+		return nil, err
+	}
+
+	// Turn it into what Nexus accepts back
+	delete(doc, "format")
+	delete(doc, "type")
+	delete(doc, "url")
+	if rule, ok := doc["routingRuleName"]; ok {
+		delete(doc, "routingRuleName")
+		if _, ok := doc["routingRule"]; !ok && rule != nil {
+			doc["routingRule"] = rule
+		}
+	}
+	return doc, nil
+}
+
+// putRepositoryDocument replaces the configuration of a repository with doc,
+// refusing when it lacks secrets Nexus would otherwise clear
+func (c Client) putRepositoryDocument(ctx context.Context, format, repositoryType, name string, doc map[string]interface{}) error {
+	if missing := missingSecrets(doc); len(missing) > 0 {
+		return fmt.Errorf("repository '%s' needs %s, which Nexus doesn't return, to be updated", name, strings.Join(missing, " and "))
+	}
+	endpoint, err := repositoryEndpoint(format, repositoryType)
+	if err != nil {
+		return err
+	}
+	return c.makeJSONRequest(ctx, "PUT", endpoint+"/"+url.PathEscape(name), nil, doc, nil)
+}
+
+// secretPaths of repository attributes Nexus accepts but never returns
+var secretPaths = map[string]bool{
+	"httpClient.authentication.password": true,
+	"aptSigning.keypair":                 true,
+	"aptSigning.passphrase":              true,
+}
+
+// missingSecrets lists the secrets the attributes in doc require but lack
+func missingSecrets(doc map[string]interface{}) []string {
+	fields := flattenDocument(doc)
+	var missing []string
+	if auth, ok := fields["httpClient.authentication.type"]; ok && auth != nil && fields["httpClient.authentication.password"] == nil {
+		missing = append(missing, "httpClient.authentication.password")
+	}
+	if _, ok := doc["aptSigning"].(map[string]interface{}); ok && fields["aptSigning.keypair"] == nil {
+		missing = append(missing, "aptSigning.keypair")
+	}
+	return missing
+}
+
+// flattenDocument returns the leaves of a decoded JSON document keyed by
+// dotted path, e.g. "storage.writePolicy". Lists are kept whole as their
+// order matters, e.g. group members.
+func flattenDocument(doc map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	var walk func(prefix string, node map[string]interface{})
+	walk = func(prefix string, node map[string]interface{}) {
+		for key, value := range node {
+			if child, ok := value.(map[string]interface{}); ok {
+				walk(prefix+key+".", child)
+				continue
+			}
+			out[prefix+key] = value
+		}
+	}
+	walk("", doc)
+	return out
+}
+
+// setDocumentPath sets the leaf at the dotted path, creating the objects
+// leading to it
+func setDocumentPath(doc map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := doc[key].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			doc[key] = child
+		}
+		doc = child
+	}
+	doc[keys[len(keys)-1]] = value
+}
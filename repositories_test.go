@@ -1,6 +1,15 @@
 package nexus
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
 
 func TestRepositories(t *testing.T) {
 	repos, err := client.Repositories()
@@ -10,3 +19,158 @@ func TestRepositories(t *testing.T) {
 	t.Logf("Results: %+v\n", repos)
 	// TODO: check for a known repo
 }
+
+func TestRepositoryManagement(t *testing.T) {
+	var requests []string
+	var payload map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"))
+	ctx := context.Background()
+
+	config := RepositoryConfig{
+		Name:          "maven-central",
+		Format:        "maven2",
+		Type:          RepositoryTypeProxy,
+		Online:        true,
+		Storage:       &StorageAttributes{BlobStoreName: "default", StrictContentTypeValidation: true},
+		Proxy:         &ProxyAttributes{RemoteURL: "https://repo1.maven.org/maven2/", ContentMaxAge: -1, MetadataMaxAge: 1440},
+		NegativeCache: &NegativeCacheAttributes{Enabled: true, TimeToLive: 1440},
+		HTTPClient:    &HTTPClientAttributes{AutoBlock: true},
+		Maven:         &MavenAttributes{VersionPolicy: "RELEASE", LayoutPolicy: "STRICT"},
+	}
+	if err := c.CreateRepository(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := payload["format"]; ok || payload["name"] != "maven-central" || payload["proxy"] == nil {
+		t.Errorf("unexpected payload %v", payload)
+	}
+
+	config.Online = false
+	if err := c.UpdateRepository(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteRepository(ctx, "maven-central"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "POST /repositories/maven/proxy,PUT /repositories/maven/proxy/maven-central,DELETE /repositories/maven-central"
+	if got := strings.Join(requests, ","); got != expected {
+		t.Errorf("unexpected requests %s", got)
+	}
+
+	// Missing format attributes are caught before sending anything
+	config.Maven = nil
+	if err := c.CreateRepository(ctx, config); err == nil || len(requests) != 3 {
+		t.Errorf("expected invalid configuration to be rejected, got %v", err)
+	}
+}
+
+func TestRepositoryLookup(t *testing.T) {
+	calls := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		switch r.URL.Path {
+		case "/repositories/maven-public":
+			w.Write([]byte(`{"name":"maven-public","format":"maven2","type":"group","url":"http://localhost/repository/maven-public"}`))
+		case "/repositories/maven/group/maven-public":
+			w.Write([]byte(`{"name":"maven-public","format":"maven2","type":"group","online":true,
+				"storage":{"blobStoreName":"default","strictContentTypeValidation":true},
+				"group":{"memberNames":["maven-releases","maven-central"]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL)
+	repo, err := c.Repository("maven-public")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Format != "maven2" || repo.BlobStoreName() != "default" || len(repo.Members()) != 2 || !repo.Online() {
+		t.Errorf("unexpected repository %+v", repo)
+	}
+	if len(calls) != 2 {
+		t.Errorf("expected a direct lookup without listing, got %v", calls)
+	}
+}
+
+func TestRepositoryLookupOlderServer(t *testing.T) {
+	calls := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		switch r.URL.Path {
+		case "/repositories":
+			w.Write([]byte(`[{"name":"maven-releases","format":"maven2","type":"hosted"}]`))
+		case "/repositorySettings":
+			w.Write([]byte(`[{"name":"maven-releases","online":true,"storage":{"blobStoreName":"default","writePolicy":"ALLOW_ONCE"}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL)
+	repo, err := c.Repository("maven-releases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.WritePolicy() != WritePolicyAllowOnce {
+		t.Errorf("unexpected repository %+v", repo)
+	}
+
+	if _, err := c.Repository("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	// Uploads only need the format, not every repository's settings
+	calls = nil
+	c, _ = New(ts.URL, WithBasicAuth("admin", "admin123"))
+	if _, err := c.UploadComponentContext(context.Background(), "maven-releases", UploadParameters{}); !errors.Is(err, ErrMissingFiles) {
+		t.Errorf("expected ErrMissingFiles, got %v", err)
+	}
+	if strings.Join(calls, ",") != "/repositories/maven-releases,/repositories" {
+		t.Errorf("unexpected requests %v", calls)
+	}
+}
+
+func TestRepositoryLookupForbiddenConfig(t *testing.T) {
+	calls := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		switch r.URL.Path {
+		case "/repositories/raw-hosted":
+			w.Write([]byte(`{"name":"raw-hosted","format":"raw","type":"hosted"}`))
+		default:
+			// Deploy only users can't read repository configurations
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithBasicAuth("deployer", "deployer123"))
+	repo, err := c.Repository("raw-hosted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Format != "raw" || repo.Config != nil || !repo.Online() {
+		t.Errorf("unexpected repository %+v", repo)
+	}
+
+	// Uploads only need the format and don't ask for the configuration
+	calls = nil
+	_, err = c.UploadComponentContext(context.Background(), "raw-hosted", UploadParameters{})
+	if !errors.Is(err, ErrMissingFiles) {
+		t.Errorf("expected ErrMissingFiles, got %v", err)
+	}
+	if strings.Join(calls, ",") != "/repositories/raw-hosted" {
+		t.Errorf("unexpected requests %v", calls)
+	}
+}
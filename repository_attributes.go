@@ -0,0 +1,138 @@
+package nexus
+
+// Repository types
+const (
+	RepositoryTypeHosted = "hosted"
+	RepositoryTypeProxy  = "proxy"
+	RepositoryTypeGroup  = "group"
+)
+
+// Write policies of hosted repositories
+const (
+	WritePolicyAllow     = "ALLOW"
+	WritePolicyAllowOnce = "ALLOW_ONCE"
+	WritePolicyDeny      = "DENY"
+)
+
+// StorageAttributes of every repository
+type StorageAttributes struct {
+	BlobStoreName               string `json:"blobStoreName"`
+	StrictContentTypeValidation bool   `json:"strictContentTypeValidation"`
+	// WritePolicy only applies to hosted repositories
+	WritePolicy string `json:"writePolicy,omitempty"`
+}
+
+// CleanupAttributes lists the cleanup policies applied to a repository
+type CleanupAttributes struct {
+	PolicyNames []string `json:"policyNames"`
+}
+
+// ComponentAttributes of hosted repositories
+type ComponentAttributes struct {
+	ProprietaryComponents bool `json:"proprietaryComponents"`
+}
+
+// ProxyAttributes of proxy repositories, ages are in minutes
+type ProxyAttributes struct {
+	RemoteURL      string `json:"remoteUrl"`
+	ContentMaxAge  int    `json:"contentMaxAge"`
+	MetadataMaxAge int    `json:"metadataMaxAge"`
+}
+
+// NegativeCacheAttributes of proxy repositories, the time to live is in minutes
+type NegativeCacheAttributes struct {
+	Enabled    bool `json:"enabled"`
+	TimeToLive int  `json:"timeToLive"`
+}
+
+// HTTPClientAttributes control how proxy repositories reach the remote
+type HTTPClientAttributes struct {
+	Blocked        bool                      `json:"blocked"`
+	AutoBlock      bool                      `json:"autoBlock"`
+	Connection     *HTTPClientConnection     `json:"connection,omitempty"`
+	Authentication *HTTPClientAuthentication `json:"authentication,omitempty"`
+}
+
+// HTTPClientConnection settings, the timeout is in seconds
+type HTTPClientConnection struct {
+	Retries                 *int   `json:"retries,omitempty"`
+	UserAgentSuffix         string `json:"userAgentSuffix,omitempty"`
+	Timeout                 *int   `json:"timeout,omitempty"`
+	EnableCircularRedirects bool   `json:"enableCircularRedirects"`
+	EnableCookies           bool   `json:"enableCookies"`
+	UseTrustStore           bool   `json:"useTrustStore"`
+}
+
+// HTTPClientAuthentication against the remote, Type is "username" or "ntlm"
+type HTTPClientAuthentication struct {
+	Type       string `json:"type"`
+	Username   string `json:"username,omitempty"`
+	Password   string `json:"password,omitempty"`
+	NTLMHost   string `json:"ntlmHost,omitempty"`
+	NTLMDomain string `json:"ntlmDomain,omitempty"`
+}
+
+// GroupAttributes of group repositories
+type GroupAttributes struct {
+	MemberNames []string `json:"memberNames"`
+	// WritableMember is only supported by docker groups
+	WritableMember string `json:"writableMember,omitempty"`
+}
+
+// MavenAttributes of maven2 repositories
+type MavenAttributes struct {
+	// VersionPolicy is RELEASE, SNAPSHOT or MIXED
+	VersionPolicy string `json:"versionPolicy"`
+	// LayoutPolicy is STRICT or PERMISSIVE
+	LayoutPolicy string `json:"layoutPolicy"`
+	// ContentDisposition is INLINE or ATTACHMENT
+	ContentDisposition string `json:"contentDisposition,omitempty"`
+}
+
+// RawAttributes of raw repositories
+type RawAttributes struct {
+	// ContentDisposition is INLINE or ATTACHMENT
+	ContentDisposition string `json:"contentDisposition,omitempty"`
+}
+
+// DockerAttributes of docker repositories
+type DockerAttributes struct {
+	V1Enabled      bool   `json:"v1Enabled"`
+	ForceBasicAuth bool   `json:"forceBasicAuth"`
+	HTTPPort       *int   `json:"httpPort,omitempty"`
+	HTTPSPort      *int   `json:"httpsPort,omitempty"`
+	Subdomain      string `json:"subdomain,omitempty"`
+}
+
+// DockerProxyAttributes of docker proxy repositories
+type DockerProxyAttributes struct {
+	// IndexType is HUB, REGISTRY or CUSTOM
+	IndexType string `json:"indexType"`
+	IndexURL  string `json:"indexUrl,omitempty"`
+}
+
+// AptAttributes of apt repositories, Flat only applies to proxies
+type AptAttributes struct {
+	Distribution string `json:"distribution"`
+	Flat         bool   `json:"flat,omitempty"`
+}
+
+// AptSigningAttributes of hosted apt repositories
+type AptSigningAttributes struct {
+	Keypair    string `json:"keypair"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// YumAttributes of hosted yum repositories
+type YumAttributes struct {
+	RepodataDepth int `json:"repodataDepth"`
+	// DeployPolicy is STRICT or PERMISSIVE
+	DeployPolicy string `json:"deployPolicy,omitempty"`
+}
+
+// NugetProxyAttributes of nuget proxy repositories
+type NugetProxyAttributes struct {
+	QueryCacheItemMaxAge int `json:"queryCacheItemMaxAge"`
+	// NugetVersion is V2 or V3
+	NugetVersion string `json:"nugetVersion,omitempty"`
+}
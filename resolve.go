@@ -0,0 +1,107 @@
+package nexus
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrAmbiguousComponent when more than one component matches an upload
+var ErrAmbiguousComponent = errors.New("more than one component matches")
+
+const (
+	// defaultResolveTimeout bounds how long an upload waits for the search
+	// index to catch up before giving up on finding the new component
+	defaultResolveTimeout = time.Second * 10
+
+	resolveMinInterval = time.Millisecond * 250
+	resolveMaxInterval = time.Second * 2
+)
+
+// WithResolveTimeout bounds how long uploads poll the search index, which
+// Nexus updates asynchronously, for the uploaded component. Zero searches once.
+func WithResolveTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.resolveTimeout = timeout
+		return nil
+	}
+}
+
+// resolveComponent finds the single component in the repository that
+// matches parameters and the exact coordinates checked by match
+func (c Client) resolveComponent(ctx context.Context, repositoryID string, parameters SearchParameters, match func(Component) bool) (*Component, error) {
+	found, err := c.resolveComponents(ctx, repositoryID, parameters, match)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) > 1 {
+		return nil, errors.Wrapf(ErrAmbiguousComponent, "%d components in '%s'", len(found), repositoryID)
+	}
+	return &found[0], nil
+}
+
+// resolveComponents finds the components in the repository that match
+// parameters and the exact coordinates checked by match, polling until at
+// least one shows up in the search index or the resolve timeout passes
+func (c Client) resolveComponents(ctx context.Context, repositoryID string, parameters SearchParameters, match func(Component) bool) ([]Component, error) {
+	parameters.Repository = repositoryID
+	deadline := time.Now().Add(c.resolveTimeout)
+	interval := resolveMinInterval
+
+	for {
+		found, err := c.findComponents(ctx, repositoryID, parameters, match)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) > 0 {
+			return found, nil
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			return nil, ErrNotFound
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if interval *= 2; interval > resolveMaxInterval {
+			interval = resolveMaxInterval
+		}
+	}
+}
+
+// findComponents searches the repository once for the components matching
+// parameters and match
+func (c Client) findComponents(ctx context.Context, repositoryID string, parameters SearchParameters, match func(Component) bool) ([]Component, error) {
+	parameters.Repository = repositoryID
+	var found []Component
+	for cpnt, err := range c.AllSearchComponents(ctx, parameters) {
+		if err != nil {
+			return nil, err
+		}
+		if cpnt.Repository == repositoryID && match(cpnt) {
+			found = append(found, cpnt)
+		}
+	}
+	return found, nil
+}
+
+// matchNameVersion matches components by exact name and version
+func matchNameVersion(name, version string) func(Component) bool {
+	return func(cpnt Component) bool {
+		return cpnt.Name == name && cpnt.Version == version
+	}
+}
+
+// matchNameVersionFold is matchNameVersion ignoring case, for formats with
+// case insensitive names
+func matchNameVersionFold(name, version string) func(Component) bool {
+	return func(cpnt Component) bool {
+		return strings.EqualFold(cpnt.Name, name) && strings.EqualFold(cpnt.Version, version)
+	}
+}
@@ -0,0 +1,147 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// searchServer answers the n-th search with the n-th list of items, repeating
+// the last one
+func searchServer(pages ...[]Component) *httptest.Server {
+	calls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		items := pages[len(pages)-1]
+		if calls < len(pages) {
+			items = pages[calls]
+		}
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	}))
+}
+
+func TestResolveComponentWaitsForIndex(t *testing.T) {
+	wanted := Component{ID: "abc", Repository: "helm-hosted", Name: "test", Version: "0.0.1"}
+	ts := searchServer(nil, nil, []Component{wanted})
+	defer ts.Close()
+
+	c, _ := New(ts.URL)
+	cpnt, err := c.resolveComponent(context.Background(), "helm-hosted", SearchParameters{}, matchNameVersion("test", "0.0.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpnt.ID != "abc" {
+		t.Errorf("unexpected component %+v", cpnt)
+	}
+}
+
+func TestResolveComponentIgnoresOtherRepositories(t *testing.T) {
+	ts := searchServer([]Component{
+		{ID: "other", Repository: "helm-proxy", Name: "test", Version: "0.0.1"},
+		{ID: "close", Repository: "helm-hosted", Name: "test", Version: "0.0.10"},
+	})
+	defer ts.Close()
+
+	c, _ := New(ts.URL, WithResolveTimeout(0))
+	if _, err := c.resolveComponent(context.Background(), "helm-hosted", SearchParameters{}, matchNameVersion("test", "0.0.1")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestResolveComponentAmbiguous(t *testing.T) {
+	ts := searchServer([]Component{
+		{ID: "a", Repository: "nuget-hosted", Name: "Example.Test", Version: "0.0.1"},
+		{ID: "b", Repository: "nuget-hosted", Name: "example.test", Version: "0.0.1"},
+	})
+	defer ts.Close()
+
+	c, _ := New(ts.URL)
+	if _, err := c.resolveComponent(context.Background(), "nuget-hosted", SearchParameters{}, matchNameVersionFold("Example.Test", "0.0.1")); !errors.Is(err, ErrAmbiguousComponent) {
+		t.Errorf("expected ErrAmbiguousComponent, got %v", err)
+	}
+}
+
+func TestUploadMaven2OnlyPOM(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "maven-releases", Group: "com.example.test", Name: "test", Version: "0.0.1"})
+	defer server.Close()
+
+	pom := `<project><parent><groupId>com.example.test</groupId><version>0.0.1</version></parent><artifactId>test</artifactId></project>`
+	upload := Maven2Upload{
+		Assets: []MavenAsset{{Source: ReaderSource("pom.xml", strings.NewReader(pom), -1), Extension: "pom"}},
+	}
+
+	c, _ := New(server.URL, WithBasicAuth("admin", "admin123"), WithResolveTimeout(time.Second))
+	component, err := c.UploadMaven2(context.Background(), "maven-releases", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" {
+		t.Errorf("unexpected component %+v", component)
+	}
+	if server.search.Get("maven.groupId") != "com.example.test" || server.search.Get("maven.artifactId") != "test" || server.search.Get("repository") != "maven-releases" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+}
+
+func TestUploadRawExactPath(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "raw-repo", Group: "/com/example/test", Name: "com/example/test/test_asset.txt"})
+	defer server.Close()
+
+	upload := RawUpload{
+		Directory: "/com/example/test",
+		Assets:    []RawAsset{{Source: ReaderSource("test_asset.txt", strings.NewReader(testContent), -1)}},
+	}
+	component, err := server.client().UploadRaw(context.Background(), "raw-repo", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.fields["raw.asset1.filename"] != "test_asset.txt" {
+		t.Errorf("unexpected component %+v, fields %v", component, server.fields)
+	}
+	if server.search.Get("name") != "com/example/test/test_asset.txt" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+}
+
+func TestUploadMaven2SnapshotIndexLag(t *testing.T) {
+	build := func(n string) Component {
+		return Component{ID: "build-" + n, Repository: "maven-snapshots", Group: "com.example.test", Name: "test", Version: "1.0-20240101.120000-" + n}
+	}
+	searches, uploaded := 0, false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			uploaded = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		// The index only shows the new build on the third search after the upload
+		items := []Component{build("9")}
+		if uploaded {
+			if searches++; searches >= 3 {
+				items = append(items, build("10"))
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	}))
+	defer ts.Close()
+
+	pom := `<project><groupId>com.example.test</groupId><artifactId>test</artifactId><version>1.0-SNAPSHOT</version></project>`
+	upload := Maven2Upload{
+		Assets: []MavenAsset{{Source: ReaderSource("pom.xml", strings.NewReader(pom), -1), Extension: "pom"}},
+	}
+
+	c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"), WithResolveTimeout(5*time.Second))
+	component, err := c.UploadMaven2(context.Background(), "maven-snapshots", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "build-10" || searches != 3 {
+		t.Errorf("expected the new build after 3 searches, got %+v after %d", component, searches)
+	}
+}
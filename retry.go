@@ -0,0 +1,226 @@
+package nexus
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy controls how requests failing with transient errors are retried
+type RetryPolicy struct {
+	// MaxAttempts including the first one, values below 2 disable retries
+	MaxAttempts int
+	// MinBackoff is the base delay, doubled for every attempt
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including Retry-After
+	MaxBackoff time.Duration
+	// RetryStatus lists the response codes worth retrying. Creates and
+	// uploads, which aren't idempotent, are only retried on 429 and 503.
+	RetryStatus []int
+}
+
+// DefaultRetryPolicy retries gateway errors and transient connection failures
+// a few times. Clients use it unless configured otherwise, pass a zero
+// RetryPolicy to WithRetryPolicy to disable retries.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  time.Millisecond * 250,
+	MaxBackoff:  time.Second * 10,
+	RetryStatus: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// WithRetryPolicy retries transient failures according to the given policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		c.retry = policy
+		return nil
+	}
+}
+
+func (p RetryPolicy) retryStatus(code int) bool {
+	for _, s := range p.RetryStatus {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (starting at 1), using
+// full jitter so concurrent clients don't retry in lock step
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	max := p.MinBackoff << uint(retry-1)
+	if max <= 0 || (p.MaxBackoff > 0 && max > p.MaxBackoff) {
+		max = p.MaxBackoff
+	}
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// replayable reports if the body of req, if any, can be sent again
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// idempotent reports if sending req twice has the same effect as once
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// unprocessed reports responses promising the request wasn't acted on, the
+// only failures non idempotent requests, like creates and uploads, may be
+// retried after
+func unprocessed(res *http.Response) bool {
+	return res != nil && (res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable)
+}
+
+// do sends req, retrying transient failures, and returns the response along
+// with its fully read body. A positive timeout limits each attempt.
+func (c Client) do(req *http.Request, timeout time.Duration) (*http.Response, []byte, error) {
+	attempts := c.retry.MaxAttempts
+	if attempts < 1 || !replayable(req) {
+		attempts = 1
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, nil, err
+			}
+			req.Body = body
+		}
+
+		res, body, err := c.send(req, timeout)
+		if attempt >= attempts || !c.shouldRetry(ctx, res, err) || !(idempotent(req) || unprocessed(res)) {
+			return res, body, err
+		}
+
+		wait := c.retry.backoff(attempt)
+		if res != nil {
+			if d, ok := retryAfter(res); ok {
+				wait = d
+				if c.retry.MaxBackoff > 0 && wait > c.retry.MaxBackoff {
+					wait = c.retry.MaxBackoff
+				}
+			}
+		}
+		c.log().Info("retrying nexus request",
+			"method", req.Method,
+			"url", req.URL.Redacted(),
+			"attempt", attempt+1,
+			"wait", wait,
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err == nil {
+				err = ctx.Err()
+			}
+			return res, body, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c Client) shouldRetry(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return transientError(err)
+	}
+	return c.retry.retryStatus(res.StatusCode)
+}
+
+// transientError reports transport failures that may succeed when tried
+// again, unlike certificate or name resolution errors
+func transientError(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	// Servers closing idle keep-alive connections surface as a plain EOF
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	if netErr.Timeout() {
+		return true
+	}
+	temporary, ok := netErr.(interface{ Temporary() bool })
+	return ok && temporary.Temporary()
+}
+
+// send performs a single attempt of req
+func (c Client) send(req *http.Request, timeout time.Duration) (*http.Response, []byte, error) {
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	c.traceRequest(req)
+	start := time.Now()
+	res, err := c.client().Do(req)
+	if err != nil {
+		c.log().Error("nexus request error", "method", req.Method, "url", req.URL.Redacted(), "error", err)
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.traceResponse(req, res, body, time.Since(start))
+	return res, body, nil
+}
@@ -0,0 +1,164 @@
+package nexus
+
+import (
+	"context"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestRetryTransientFailures(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy
+	policy.MinBackoff = time.Millisecond
+	c, _ := New(ts.URL, WithRetryPolicy(policy))
+	if err := c.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy
+	policy.MaxAttempts = 2
+	policy.MinBackoff = time.Millisecond
+	c, _ := New(ts.URL, WithRetryPolicy(policy))
+	if err := c.Ping(); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestRetryNotReplayable(t *testing.T) {
+	req, _ := http.NewRequest("POST", "http://localhost", http.NoBody)
+	req.Body = ioutil.NopCloser(strings.NewReader("{}"))
+	req.GetBody = nil
+	if replayable(req) {
+		t.Error("POST without GetBody should not be replayable")
+	}
+	if idempotent(req) {
+		t.Error("POST should not be idempotent")
+	}
+}
+
+func TestRetryCreates(t *testing.T) {
+	for status, expected := range map[int]int{
+		http.StatusBadGateway:         1,
+		http.StatusGatewayTimeout:     1,
+		http.StatusServiceUnavailable: 2,
+		http.StatusTooManyRequests:    2,
+	} {
+		calls := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+		}))
+
+		policy := DefaultRetryPolicy
+		policy.MaxAttempts = 2
+		c, _ := New(ts.URL, WithBasicAuth("admin", "admin123"), WithRetryPolicy(policy))
+		// The create may have gone through before a gateway error
+		if err := c.CreateCleanupPolicy(context.Background(), CleanupPolicy{Name: "weekly", Format: "raw"}); err == nil {
+			t.Errorf("%d: expected error", status)
+		}
+		if calls != expected {
+			t.Errorf("%d: expected %d attempts, got %d", status, expected, calls)
+		}
+		ts.Close()
+	}
+}
+
+func TestRetryByDefault(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	c, _ := New(ts.URL)
+	if err := c.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+
+	calls = 0
+	c, _ = New(ts.URL, WithRetryPolicy(RetryPolicy{}))
+	if err := c.Ping(); err == nil || calls != 1 {
+		t.Errorf("expected a single failed attempt with retries disabled, got %d: %v", calls, err)
+	}
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, 2},
+		{"timeout", &net.DNSError{Err: "i/o timeout", Name: "nexus", IsTimeout: true}, 2},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "nexus", IsNotFound: true}, 1},
+		{"bad certificate", x509.UnknownAuthorityError{}, 1},
+	}
+
+	for _, test := range tests {
+		calls := 0
+		transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return nil, test.err
+		})
+		policy := DefaultRetryPolicy
+		policy.MaxAttempts = 2
+		policy.MinBackoff = time.Millisecond
+		c, _ := New("http://nexus/service/rest/v1", WithTransport(transport), WithRetryPolicy(policy))
+
+		err := c.Ping()
+		var urlErr *url.Error
+		if !errors.As(err, &urlErr) {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if calls != test.expected {
+			t.Errorf("%s: expected %d attempts, got %d", test.name, test.expected, calls)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package nexus
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -63,6 +64,11 @@ func searchEscapeVersion(in string) string {
 
 // SearchComponents via end point
 func (c Client) SearchComponents(parameters SearchParameters) ([]Component, string, error) {
+	return c.SearchComponentsContext(context.Background(), parameters)
+}
+
+// SearchComponentsContext via end point
+func (c Client) SearchComponentsContext(ctx context.Context, parameters SearchParameters) ([]Component, string, error) {
 	args, _ := structToMap(parameters, true)
 	// args["version"] = searchEscapeVersion(fmt.Sprintf("%v", args["version"]))
 
@@ -70,7 +76,7 @@ func (c Client) SearchComponents(parameters SearchParameters) ([]Component, stri
 		Items             []Component `json:"items"`
 		ContinuationToken string      `json:"continuationToken"`
 	}{}
-	if err := c.makeRequest("GET", "/search", args, &result); err != nil {
+	if err := c.makeRequest(ctx, "GET", "/search", args, &result); err != nil {
 		return nil, "", err
 	}
 	return result.Items, result.ContinuationToken, nil
@@ -78,6 +84,11 @@ func (c Client) SearchComponents(parameters SearchParameters) ([]Component, stri
 
 // SearchAssets via end point
 func (c Client) SearchAssets(parameters SearchParameters) ([]Asset, string, error) {
+	return c.SearchAssetsContext(context.Background(), parameters)
+}
+
+// SearchAssetsContext via end point
+func (c Client) SearchAssetsContext(ctx context.Context, parameters SearchParameters) ([]Asset, string, error) {
 	args, _ := structToMap(parameters, true)
 	// args["version"] = searchEscapeVersion(fmt.Sprintf("%v", args["version"]))
 
@@ -85,7 +96,7 @@ func (c Client) SearchAssets(parameters SearchParameters) ([]Asset, string, erro
 		Items             []Asset `json:"items"`
 		ContinuationToken string  `json:"continuationToken"`
 	}{}
-	if err := c.makeRequest("GET", "/search/assets", args, &result); err != nil {
+	if err := c.makeRequest(ctx, "GET", "/search/assets", args, &result); err != nil {
 		return nil, "", err
 	}
 	return result.Items, result.ContinuationToken, nil
@@ -1,18 +1,7 @@
 package nexus
 
-import (
-	"bytes"
-	"fmt"
-	"io"
-	"mime/multipart"
-	"os"
-	"strconv"
-	"strings"
-
-	"github.com/pkg/errors"
-)
-
-// UploadParameters for uploading files to nexus
+// UploadParameters for uploading files to nexus. Formats with more than
+// three assets are better served by the typed uploads, e.g. Maven2Upload.
 type UploadParameters struct {
 	RubyGemsAsset          string `json:"rubygems.asset"`
 	NugetAsset             string `json:"nuget.asset"`
@@ -39,242 +28,35 @@ type UploadParameters struct {
 	Maven2Asset3           string `json:"maven2.asset3"`
 	Maven2Asset3Classifier string `json:"maven2.asset3.classifier"`
 	Maven2Asset3Extension  string `json:"maven2.asset3.extension"`
-}
-
-func parseFileUpload(w *multipart.Writer, key, filename string) (io.Writer, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	part, err := w.CreateFormFile(key, filename)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = io.Copy(part, file)
-	if err != nil {
-		return nil, err
-	}
-	return part, nil
-}
-
-type maven2File struct {
-	Label      string
-	AssetPath  string
-	Classifier string
-	Extension  string
-}
-
-func (c Client) uploadMaven2Component(rID string, p UploadParameters) (*Component, error) {
-	// Validate parameters
-	// Starting with the files
-	files := make([]maven2File, 0)
-	if p.Maven2Asset1 != "" {
-		files = append(files, maven2File{
-			Label:      "maven2.asset1",
-			AssetPath:  p.Maven2Asset1,
-			Classifier: p.Maven2Asset1Classifier,
-			Extension:  p.Maven2Asset1Extension,
-		})
-	}
-	if p.Maven2Asset2 != "" {
-		files = append(files, maven2File{
-			Label:      "maven2.asset2",
-			AssetPath:  p.Maven2Asset2,
-			Classifier: p.Maven2Asset2Classifier,
-			Extension:  p.Maven2Asset2Extension,
-		})
-	}
-	if p.Maven2Asset3 != "" {
-		files = append(files, maven2File{
-			Label:      "maven2.asset3",
-			AssetPath:  p.Maven2Asset3,
-			Classifier: p.Maven2Asset3Classifier,
-			Extension:  p.Maven2Asset3Extension,
-		})
-	}
-
-	if len(files) == 0 {
-		return nil, errors.Wrap(ErrMissingFiles, "uploadMaven2Component")
-	}
-
-	pomSupplied := false
-	for _, f := range files {
-		if strings.ToLower(f.Extension) == "pom" {
-			pomSupplied = true
-		}
-
-		if f.Extension == "" {
-			return nil, fmt.Errorf("uploadMaven2Component: missing extension for asset '%s'", f.AssetPath)
-		}
-	}
-
-	if !pomSupplied {
-		if p.Maven2GroupID == "" {
-			return nil, fmt.Errorf("uploadMaven2Component: missing group id")
-		}
-		if p.Maven2ArtifactID == "" {
-			return nil, fmt.Errorf("uploadMaven2Component: missing artifact id")
-		}
-		if p.Maven2Version == "" {
-			return nil, fmt.Errorf("uploadMaven2Component: missing version")
-		}
-	}
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	for _, f := range files {
-		if _, err := parseFileUpload(writer, f.Label, f.AssetPath); err != nil {
-			return nil, errors.Wrap(err, "uploadMaven2Component - parseFileUpload")
-		}
-		_ = writer.WriteField(fmt.Sprintf("%s.classifier", f.Label), f.Classifier)
-		_ = writer.WriteField(fmt.Sprintf("%s.extension", f.Label), f.Extension)
-	}
-
-	// ---
-	if p.Maven2GroupID != "" {
-		_ = writer.WriteField("maven2.groupId", p.Maven2GroupID)
-	}
-	if p.Maven2ArtifactID != "" {
-		_ = writer.WriteField("maven2.artifactId", p.Maven2ArtifactID)
-	}
-	if p.Maven2Version != "" {
-		_ = writer.WriteField("maven2.version", p.Maven2Version)
-	}
-	if p.Maven2GeneratePOM != nil {
-		_ = writer.WriteField("maven2.generate-pom", strconv.FormatBool(*p.Maven2GeneratePOM))
-	}
-	if p.Maven2Packaging != "" {
-		_ = writer.WriteField("maven2.packaging", p.Maven2Packaging)
-	}
-
-	headers := map[string]string{"Content-Type": writer.FormDataContentType()}
-
-	// Close writer so we can pass it to the http request
-	if err := writer.Close(); err != nil {
-		return nil, errors.Wrap(err, "uploadMaven2Component - close")
-	}
-
-	err := c.makeMultiPartRequest("POST", "/components", map[string]interface{}{"repository": rID}, headers, body, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Query the artifact
-	parameters := SearchParameters{
-		MavenGroupID:     p.Maven2GroupID,
-		MavenArtifactID:  p.Maven2ArtifactID,
-		MavenBaseVersion: p.Maven2Version,
-	}
-	// log.Printf("Params: %#v\n", parameters)
-
-	cpnts, _, err := c.SearchComponents(parameters)
-	if err != nil {
-		return nil, err
-	}
-	if len(cpnts) == 0 {
-		return nil, ErrNotFound
-	}
-	return &cpnts[0], nil
-}
-
-type rawFile struct {
-	Label        string
-	SourceAsset  string
-	DestFileName string
-}
-
-func (c Client) uploadRawComponent(rID string, p UploadParameters) (*Component, error) {
-	// Validate parameters
-	// Starting with the files
-	files := make([]rawFile, 0)
-	if p.RawAsset1 != "" {
-		files = append(files, rawFile{
-			Label:        "raw.asset1",
-			SourceAsset:  p.RawAsset1,
-			DestFileName: p.RawAsset1Filename,
-		})
-	}
-	if p.RawAsset2 != "" {
-		files = append(files, rawFile{
-			Label:        "raw.asset2",
-			SourceAsset:  p.RawAsset2,
-			DestFileName: p.RawAsset2Filename,
-		})
-	}
-	if p.RawAsset3 != "" {
-		files = append(files, rawFile{
-			Label:        "raw.asset3",
-			SourceAsset:  p.RawAsset3,
-			DestFileName: p.RawAsset3Filename,
-		})
-	}
-
-	if len(files) == 0 {
-		return nil, errors.Wrap(ErrMissingFiles, "uploadRawComponent")
-	}
-
-	if p.RawDirectory == "" {
-		return nil, fmt.Errorf("uploadRawComponent: missing upload directory")
-	}
-
-	// Process the information
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	for _, f := range files {
-		if _, err := parseFileUpload(writer, f.Label, f.SourceAsset); err != nil {
-			return nil, errors.Wrap(err, "uploadRawComponent - parseFileUpload")
-		}
-		_ = writer.WriteField(fmt.Sprintf("%s.filename", f.Label), f.DestFileName)
-	}
-
-	_ = writer.WriteField("raw.directory", p.RawDirectory)
-
-	headers := map[string]string{"Content-Type": writer.FormDataContentType()}
-
-	// Close writer so we can pass it to the http request
-	if err := writer.Close(); err != nil {
-		return nil, errors.Wrap(err, "uploadRawComponent - close")
-	}
-
-	err := c.makeMultiPartRequest("POST", "/components", map[string]interface{}{"repository": rID}, headers, body, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Query the artifact
-	parameters := SearchParameters{
-		Format: "raw",
-		Query:  files[0].DestFileName,
-	}
-	// log.Printf("Params: %#v\n", parameters)
-
-	cpnts, _, err := c.SearchComponents(parameters)
-	if err != nil {
-		return nil, err
-	}
-	if len(cpnts) == 0 {
-		return nil, ErrNotFound
-	}
-	return &cpnts[0], nil
-}
-
-func (c Client) uploadPyPiComponent(rID string, p UploadParameters) (*Component, error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-func (c Client) uploadRubyGemsComponent(rID string, p UploadParameters) (*Component, error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-func (c Client) uploadNugetComponent(rID string, p UploadParameters) (*Component, error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-func (c Client) uploadNPMComponent(rID string, p UploadParameters) (*Component, error) {
-	return nil, fmt.Errorf("not implemented")
+	AptAsset               string `json:"apt.asset"`
+	YumDirectory           string `json:"yum.directory"`
+	YumAsset               string `json:"yum.asset"`
+	YumAssetFilename       string `json:"yum.asset.filename"`
+	HelmAsset              string `json:"helm.asset"`
+	RAsset                 string `json:"r.asset"`
+	RAssetPathID           string `json:"r.asset.pathId"`
+
+	// Sources supply asset content from any io.Reader, keyed by the form
+	// field they replace, e.g. "maven2.asset1". They take precedence over
+	// the file paths above.
+	Sources map[string]UploadSource `json:"-"`
+	// NPMOverwrite uploads the npm package even when its version already exists
+	NPMOverwrite bool `json:"-"`
+	// Fields holds extra form fields for Uploaders of other formats
+	Fields map[string]string `json:"-"`
+	// Progress is notified as the upload is sent, use ProgressFunc when only
+	// the bytes sent are of interest
+	Progress UploadProgress `json:"-"`
+}
+
+// source returns the content of the asset for the form field label, which is
+// either one of Sources or the file at path
+func (p UploadParameters) source(label, path string) (UploadSource, bool) {
+	if src, ok := p.Sources[label]; ok {
+		return src, true
+	}
+	if path != "" {
+		return FileSource(path), true
+	}
+	return UploadSource{}, false
 }
@@ -0,0 +1,182 @@
+package nexus
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"github.com/ulikunitz/xz"
+)
+
+// AptUpload describes a .deb package
+type AptUpload struct {
+	Asset UploadSource
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// DebPackage identifies an uploaded package
+type DebPackage struct {
+	Package      string
+	Version      string
+	Architecture string
+}
+
+// arMagic starts every ar archive, which .deb packages are
+const arMagic = "!<arch>\n"
+
+// readArMember returns a reader for the first member of the ar archive whose
+// name matches
+func readArMember(r io.Reader, match func(name string) bool) (string, io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != arMagic {
+		return "", nil, fmt.Errorf("not a debian package")
+	}
+
+	header := make([]byte, 60)
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			if err == io.EOF {
+				return "", nil, ErrMissingMetadata
+			}
+			return "", nil, err
+		}
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid ar header for '%s'", name)
+		}
+
+		if match(name) {
+			return name, io.LimitReader(br, size), nil
+		}
+		// Members are padded to an even size
+		if _, err := br.Discard(int(size + size%2)); err != nil {
+			return "", nil, err
+		}
+	}
+}
+
+// parseControl reads the fields of a debian control file
+func parseControl(data []byte) map[string]string {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			// Continuation lines only matter for descriptions
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 {
+			fields[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return fields
+}
+
+// readDebPackage extracts the control file of the package in f
+func readDebPackage(f *os.File) (DebPackage, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return DebPackage{}, err
+	}
+
+	name, member, err := readArMember(f, func(name string) bool { return strings.HasPrefix(name, "control.tar") })
+	if err != nil {
+		return DebPackage{}, err
+	}
+	// gzip was the default of older dpkg, current versions use xz and
+	// Ubuntu uses zstd
+	switch name {
+	case "control.tar":
+	case "control.tar.gz":
+		gz, err := gzip.NewReader(member)
+		if err != nil {
+			return DebPackage{}, err
+		}
+		defer gz.Close()
+		member = gz
+	case "control.tar.xz":
+		xr, err := xz.NewReader(member)
+		if err != nil {
+			return DebPackage{}, err
+		}
+		member = xr
+	case "control.tar.zst":
+		zr, err := zstd.NewReader(member)
+		if err != nil {
+			return DebPackage{}, err
+		}
+		defer zr.Close()
+		member = zr
+	default:
+		return DebPackage{}, fmt.Errorf("unsupported control archive '%s'", name)
+	}
+
+	data, err := readTarFile(member, func(name string) bool { return path.Clean(name) == "control" })
+	if err != nil {
+		return DebPackage{}, err
+	}
+
+	fields := parseControl(data)
+	pkg := DebPackage{
+		Package:      fields["Package"],
+		Version:      fields["Version"],
+		Architecture: fields["Architecture"],
+	}
+	if pkg.Package == "" || pkg.Version == "" {
+		return DebPackage{}, ErrMissingMetadata
+	}
+	return pkg, nil
+}
+
+// UploadApt package to an apt repository
+func (c Client) UploadApt(ctx context.Context, repositoryID string, upload AptUpload) (*Component, error) {
+	if upload.Asset.open == nil {
+		return nil, errors.Wrap(ErrMissingFiles, "UploadApt")
+	}
+
+	file, src, cleanup, err := localSource(upload.Asset)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadApt")
+	}
+	defer cleanup()
+
+	pkg, err := readDebPackage(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadApt")
+	}
+
+	form := newMultipartForm()
+	form.AddFile("apt.asset", src)
+
+	err = c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, form, upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the package, Nexus groups it by architecture
+	parameters := SearchParameters{
+		Format:  "apt",
+		Name:    pkg.Package,
+		Version: pkg.Version,
+	}
+	return c.resolveComponent(ctx, repositoryID, parameters, func(cpnt Component) bool {
+		return matchNameVersion(pkg.Package, pkg.Version)(cpnt) && (cpnt.Group == "" || cpnt.Group == pkg.Architecture)
+	})
+}
+
+func (c Client) uploadAptComponent(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	src, _ := p.source("apt.asset", p.AptAsset)
+	return c.UploadApt(ctx, rID, AptUpload{Asset: src, Progress: p.Progress})
+}
@@ -0,0 +1,103 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// arArchive builds an ar archive holding the given members in order
+func arArchive(members ...[2]string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(arMagic)
+	for _, m := range members {
+		fmt.Fprintf(&buf, "%-16s%-12s%-6s%-6s%-8s%-10d`\n", m[0], "0", "0", "0", "100644", len(m[1]))
+		buf.WriteString(m[1])
+		if len(m[1])%2 != 0 {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestUploadApt(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "apt-hosted", Format: "apt", Name: "test", Version: "0.0.1-1"})
+	defer server.Close()
+
+	control := "Package: test\nVersion: 0.0.1-1\nArchitecture: amd64\nDescription: testing\n more testing\n"
+	deb := arArchive(
+		[2]string{"debian-binary", "2.0\n"},
+		[2]string{"control.tar.gz", string(gzipData(t, tarArchive(t, map[string]string{"./control": control})))},
+		[2]string{"data.tar.gz", string(gzipData(t, tarArchive(t, map[string]string{})))},
+	)
+	upload := AptUpload{Asset: ReaderSource("test_0.0.1-1_amd64.deb", bytes.NewReader(deb), -1)}
+
+	component, err := server.client().UploadApt(context.Background(), "apt-hosted", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.files["apt.asset"] != "test_0.0.1-1_amd64.deb" {
+		t.Errorf("unexpected component %+v, files %v", component, server.files)
+	}
+	if server.search.Get("name") != "test" || server.search.Get("version") != "0.0.1-1" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+}
+
+func TestUploadAptCompressedControl(t *testing.T) {
+	control := tarArchive(t, map[string]string{"./control": "Package: test\nVersion: 1:0.0.1-1\nArchitecture: amd64\n"})
+
+	var xzData bytes.Buffer
+	xw, err := xz.NewWriter(&xzData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xw.Write(control)
+	xw.Close()
+
+	zw, _ := zstd.NewWriter(nil)
+	zstdData := zw.EncodeAll(control, nil)
+
+	for member, data := range map[string][]byte{"control.tar.xz": xzData.Bytes(), "control.tar.zst": zstdData} {
+		server := newUploadServer(t, Component{ID: "abc", Repository: "apt-hosted", Format: "apt", Group: "amd64", Name: "test", Version: "1:0.0.1-1"})
+
+		// The package is identified by its control file, not its file name
+		deb := arArchive(
+			[2]string{"debian-binary", "2.0\n"},
+			[2]string{member, string(data)},
+			[2]string{"data.tar.xz", ""},
+		)
+		upload := AptUpload{Asset: ReaderSource("app.deb", bytes.NewReader(deb), -1)}
+
+		component, err := server.client().UploadApt(context.Background(), "apt-hosted", upload)
+		if err != nil {
+			t.Fatalf("%s: %s", member, err)
+		}
+		if component.ID != "abc" || server.search.Get("name") != "test" || server.search.Get("version") != "1:0.0.1-1" {
+			t.Errorf("%s: unexpected component %+v, search %v", member, component, server.search)
+		}
+		server.Close()
+	}
+}
+
+func TestUploadAptUnsupportedControl(t *testing.T) {
+	server := newUploadServer(t, Component{})
+	defer server.Close()
+
+	deb := arArchive(
+		[2]string{"debian-binary", "2.0\n"},
+		[2]string{"control.tar.bz2", "BZh"},
+	)
+	upload := AptUpload{Asset: ReaderSource("app.deb", bytes.NewReader(deb), -1)}
+
+	if _, err := server.client().UploadApt(context.Background(), "apt-hosted", upload); err == nil {
+		t.Error("expected unsupported control archive to be rejected")
+	}
+	if server.files != nil {
+		t.Errorf("expected nothing to be uploaded, got %v", server.files)
+	}
+}
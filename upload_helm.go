@@ -0,0 +1,96 @@
+package nexus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HelmUpload describes a packaged helm chart
+type HelmUpload struct {
+	Asset UploadSource
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// HelmChart identifies an uploaded chart
+type HelmChart struct {
+	Name    string
+	Version string
+}
+
+// readHelmChart extracts the top level keys of {chart}/Chart.yaml in f
+func readHelmChart(f *os.File) (HelmChart, error) {
+	data, err := readTarGzFile(f, func(name string) bool {
+		return pathDepth(name) == 2 && strings.HasSuffix(name, "/Chart.yaml")
+	})
+	if err != nil {
+		return HelmChart{}, err
+	}
+
+	var chart HelmChart
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		switch key, value := splitYAMLLine(line); key {
+		case "name":
+			chart.Name = value
+		case "version":
+			chart.Version = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return HelmChart{}, err
+	}
+
+	if chart.Name == "" || chart.Version == "" {
+		return HelmChart{}, ErrMissingMetadata
+	}
+	return chart, nil
+}
+
+// UploadHelm chart to a helm repository
+func (c Client) UploadHelm(ctx context.Context, repositoryID string, upload HelmUpload) (*Component, error) {
+	if upload.Asset.open == nil {
+		return nil, errors.Wrap(ErrMissingFiles, "UploadHelm")
+	}
+
+	file, src, cleanup, err := localSource(upload.Asset)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadHelm")
+	}
+	defer cleanup()
+
+	chart, err := readHelmChart(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadHelm")
+	}
+
+	form := newMultipartForm()
+	form.AddFile("helm.asset", src)
+
+	err = c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, form, upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the chart
+	parameters := SearchParameters{
+		Format:  "helm",
+		Name:    chart.Name,
+		Version: chart.Version,
+	}
+	return c.resolveComponent(ctx, repositoryID, parameters, matchNameVersion(chart.Name, chart.Version))
+}
+
+func (c Client) uploadHelmComponent(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	src, _ := p.source("helm.asset", p.HelmAsset)
+	return c.UploadHelm(ctx, rID, HelmUpload{Asset: src, Progress: p.Progress})
+}
@@ -0,0 +1,29 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestUploadHelm(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "helm-hosted", Format: "helm", Name: "test", Version: "0.0.1"})
+	defer server.Close()
+
+	chart := gzipData(t, tarArchive(t, map[string]string{
+		"test/Chart.yaml":              "apiVersion: v2\nname: test\nversion: \"0.0.1\"\ndependencies:\n  - name: other\n    version: 1.0.0\n",
+		"test/charts/other/Chart.yaml": "name: other\nversion: 1.0.0\n",
+	}))
+	upload := HelmUpload{Asset: ReaderSource("test-0.0.1.tgz", bytes.NewReader(chart), -1)}
+
+	component, err := server.client().UploadHelm(context.Background(), "helm-hosted", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.files["helm.asset"] != "test-0.0.1.tgz" {
+		t.Errorf("unexpected component %+v, files %v", component, server.files)
+	}
+	if server.search.Get("name") != "test" || server.search.Get("version") != "0.0.1" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+}
@@ -0,0 +1,303 @@
+package nexus
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MavenAsset is a single file of a maven2 component
+type MavenAsset struct {
+	Source     UploadSource
+	Classifier string
+	Extension  string
+}
+
+// Maven2Upload describes a maven2 component made up of any number of assets
+type Maven2Upload struct {
+	GroupID     string
+	ArtifactID  string
+	Version     string
+	GeneratePOM *bool
+	Packaging   string
+	Assets      []MavenAsset
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// maven2Upload adapts the fixed maven2.asset1..3 fields
+func (p UploadParameters) maven2Upload() Maven2Upload {
+	u := Maven2Upload{
+		GroupID:     p.Maven2GroupID,
+		ArtifactID:  p.Maven2ArtifactID,
+		Version:     p.Maven2Version,
+		GeneratePOM: p.Maven2GeneratePOM,
+		Packaging:   p.Maven2Packaging,
+		Progress:    p.Progress,
+	}
+
+	assets := []struct{ path, classifier, extension string }{
+		{p.Maven2Asset1, p.Maven2Asset1Classifier, p.Maven2Asset1Extension},
+		{p.Maven2Asset2, p.Maven2Asset2Classifier, p.Maven2Asset2Extension},
+		{p.Maven2Asset3, p.Maven2Asset3Classifier, p.Maven2Asset3Extension},
+	}
+	for i, a := range assets {
+		if src, ok := p.source(fmt.Sprintf("maven2.asset%d", i+1), a.path); ok {
+			u.Assets = append(u.Assets, MavenAsset{
+				Source:     src,
+				Classifier: a.classifier,
+				Extension:  a.extension,
+			})
+		}
+	}
+	return u
+}
+
+// validate the upload before anything is sent
+func (u Maven2Upload) validate() error {
+	if len(u.Assets) == 0 {
+		return ErrMissingFiles
+	}
+
+	pomSupplied := false
+	for _, a := range u.Assets {
+		if strings.ToLower(a.Extension) == "pom" {
+			pomSupplied = true
+		}
+
+		if a.Extension == "" {
+			return fmt.Errorf("missing extension for asset '%s'", a.Source.Filename)
+		}
+	}
+
+	if !pomSupplied {
+		if u.GroupID == "" {
+			return fmt.Errorf("missing group id")
+		}
+		if u.ArtifactID == "" {
+			return fmt.Errorf("missing artifact id")
+		}
+		if u.Version == "" {
+			return fmt.Errorf("missing version")
+		}
+	}
+	return nil
+}
+
+// form encodes the upload, numbering the assets maven2.asset1..N
+func (u Maven2Upload) form() *multipartForm {
+	form := newMultipartForm()
+
+	for i, a := range u.Assets {
+		label := fmt.Sprintf("maven2.asset%d", i+1)
+		form.AddFile(label, a.Source)
+		form.WriteField(label+".classifier", a.Classifier)
+		form.WriteField(label+".extension", a.Extension)
+	}
+
+	if u.GroupID != "" {
+		form.WriteField("maven2.groupId", u.GroupID)
+	}
+	if u.ArtifactID != "" {
+		form.WriteField("maven2.artifactId", u.ArtifactID)
+	}
+	if u.Version != "" {
+		form.WriteField("maven2.version", u.Version)
+	}
+	if u.GeneratePOM != nil {
+		form.WriteField("maven2.generate-pom", strconv.FormatBool(*u.GeneratePOM))
+	}
+	if u.Packaging != "" {
+		form.WriteField("maven2.packaging", u.Packaging)
+	}
+	return form
+}
+
+// mavenPOM holds the coordinates declared by a pom, which may be inherited
+// from its parent
+type mavenPOM struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+}
+
+// coordinates returns the group, artifact and version of the upload, reading
+// any not given explicitly from the supplied POM. The POM's source is
+// replaced when it had to be spooled, cleanup must be called once done.
+func (u *Maven2Upload) coordinates() (string, string, string, func(), error) {
+	cleanup := func() {}
+	if u.GroupID != "" && u.ArtifactID != "" && u.Version != "" {
+		return u.GroupID, u.ArtifactID, u.Version, cleanup, nil
+	}
+
+	for i, a := range u.Assets {
+		if strings.ToLower(a.Extension) != "pom" || a.Classifier != "" {
+			continue
+		}
+
+		file, src, done, err := localSource(a.Source)
+		if err != nil {
+			return "", "", "", cleanup, err
+		}
+		u.Assets[i].Source = src
+
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			done()
+			return "", "", "", cleanup, err
+		}
+		data, err := ioutil.ReadAll(io.LimitReader(file, maxMetadataSize))
+		if err != nil {
+			done()
+			return "", "", "", cleanup, err
+		}
+
+		var pom mavenPOM
+		if err := xml.Unmarshal(data, &pom); err != nil {
+			done()
+			return "", "", "", cleanup, errors.Wrap(err, "invalid pom")
+		}
+		group, artifact, version := u.GroupID, u.ArtifactID, u.Version
+		if group == "" {
+			group = firstNonEmpty(pom.GroupID, pom.Parent.GroupID)
+		}
+		if artifact == "" {
+			artifact = pom.ArtifactID
+		}
+		if version == "" {
+			version = firstNonEmpty(pom.Version, pom.Parent.Version)
+		}
+		if group == "" || artifact == "" || version == "" {
+			done()
+			return "", "", "", cleanup, ErrMissingMetadata
+		}
+		return group, artifact, version, done, nil
+	}
+	return "", "", "", cleanup, ErrMissingMetadata
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// UploadMaven2 component to a maven2 repository
+func (c Client) UploadMaven2(ctx context.Context, repositoryID string, upload Maven2Upload) (*Component, error) {
+	if err := upload.validate(); err != nil {
+		return nil, errors.Wrap(err, "UploadMaven2")
+	}
+
+	// Copy the assets, coordinates may replace the POM's source
+	upload.Assets = append([]MavenAsset(nil), upload.Assets...)
+	group, artifact, version, cleanup, err := upload.coordinates()
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadMaven2")
+	}
+	defer cleanup()
+
+	parameters := SearchParameters{
+		Format:           "maven2",
+		MavenGroupID:     group,
+		MavenArtifactID:  artifact,
+		MavenBaseVersion: version,
+	}
+
+	// Every snapshot deployed is its own timestamped component, the upload
+	// is the first build newer than the ones already deployed
+	snapshot := strings.HasSuffix(version, "-SNAPSHOT")
+	prefix := strings.TrimSuffix(version, "SNAPSHOT")
+	isBuild := func(cpnt Component) bool {
+		return cpnt.Group == group && cpnt.Name == artifact && strings.HasPrefix(cpnt.Version, prefix)
+	}
+	previous := ""
+	if snapshot {
+		existing, err := c.findComponents(ctx, repositoryID, parameters, isBuild)
+		if err != nil {
+			return nil, errors.Wrap(err, "UploadMaven2")
+		}
+		for _, cpnt := range existing {
+			if newerSnapshot(cpnt.Version, previous, prefix) {
+				previous = cpnt.Version
+			}
+		}
+	}
+
+	err = c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, upload.form(), upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the artifact
+	if !snapshot {
+		return c.resolveComponent(ctx, repositoryID, parameters, func(cpnt Component) bool {
+			return cpnt.Group == group && cpnt.Name == artifact && cpnt.Version == version
+		})
+	}
+
+	cpnts, err := c.resolveComponents(ctx, repositoryID, parameters, func(cpnt Component) bool {
+		// Wait for the index to catch up with the upload
+		return isBuild(cpnt) && (previous == "" || newerSnapshot(cpnt.Version, previous, prefix))
+	})
+	if err != nil {
+		return nil, err
+	}
+	latest := cpnts[0]
+	for _, cpnt := range cpnts[1:] {
+		if newerSnapshot(cpnt.Version, latest.Version, prefix) {
+			latest = cpnt
+		}
+	}
+	return &latest, nil
+}
+
+// newerSnapshot reports if snapshot version a was deployed after b. Versions
+// are prefix followed by a yyyyMMdd.HHmmss timestamp and a build number,
+// e.g. 1.0-20240101.120000-10.
+func newerSnapshot(a, b, prefix string) bool {
+	aTime, aBuild, aOK := snapshotBuild(a, prefix)
+	bTime, bBuild, bOK := snapshotBuild(b, prefix)
+	switch {
+	case aOK != bOK:
+		return aOK
+	case aTime != bTime:
+		// Fixed width, so comparing them as strings orders them by time
+		return aTime > bTime
+	default:
+		return aBuild > bBuild
+	}
+}
+
+// snapshotBuild splits a timestamped snapshot version into its timestamp and
+// build number
+func snapshotBuild(version, prefix string) (string, int, bool) {
+	parts := strings.Split(strings.TrimPrefix(version, prefix), "-")
+	if len(parts) != 2 || len(parts[0]) != len("20060102.150405") {
+		return "", 0, false
+	}
+	if _, err := time.Parse("20060102.150405", parts[0]); err != nil {
+		return "", 0, false
+	}
+	build, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, false
+	}
+	return parts[0], build, true
+}
+
+func (c Client) uploadMaven2Component(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	return c.UploadMaven2(ctx, rID, p.maven2Upload())
+}
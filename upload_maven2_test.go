@@ -0,0 +1,94 @@
+package nexus
+
+import (
+	"context"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+// formFields decodes a form returning the plain fields and the file fields
+func formFields(t *testing.T, form *multipartForm) (map[string]string, map[string]string) {
+	_, params, err := mime.ParseMediaType(form.FormDataContentType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := multipart.NewReader(form.reader(context.Background(), nil), params["boundary"])
+	parsed, err := r.ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := map[string]string{}
+	for key, values := range parsed.Value {
+		fields[key] = values[0]
+	}
+	files := map[string]string{}
+	for key, headers := range parsed.File {
+		files[key] = headers[0].Filename
+	}
+	return fields, files
+}
+
+func TestMaven2UploadManyAssets(t *testing.T) {
+	upload := Maven2Upload{GroupID: "com.example.test", ArtifactID: "test", Version: "0.0.1"}
+	for _, ext := range []string{"jar", "pom", "jar.asc", "pom.asc", "jar", "jar"} {
+		upload.Assets = append(upload.Assets, MavenAsset{
+			Source:    ReaderSource("test."+ext, strings.NewReader(testContent), -1),
+			Extension: ext,
+		})
+	}
+	upload.Assets[4].Classifier = "sources"
+	upload.Assets[5].Classifier = "javadoc"
+
+	if err := upload.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	fields, files := formFields(t, upload.form())
+	if len(files) != 6 || files["maven2.asset6"] != "test.jar" {
+		t.Errorf("unexpected files %v", files)
+	}
+	if fields["maven2.asset6.classifier"] != "javadoc" || fields["maven2.asset4.extension"] != "pom.asc" {
+		t.Errorf("unexpected fields %v", fields)
+	}
+}
+
+func TestMaven2UploadParametersAdapter(t *testing.T) {
+	params := UploadParameters{
+		Maven2GroupID:         "com.example.test",
+		Maven2ArtifactID:      "test",
+		Maven2Version:         "0.0.1",
+		Maven2Asset2:          "/tmp/test_asset.txt",
+		Maven2Asset2Extension: "txt",
+		Sources: map[string]UploadSource{
+			"maven2.asset1": ReaderSource("test.pom", strings.NewReader(testContent), -1),
+		},
+		Maven2Asset1Extension: "pom",
+	}
+
+	upload := params.maven2Upload()
+	if len(upload.Assets) != 2 || upload.Assets[0].Extension != "pom" || upload.Assets[1].Source.Filename != "test_asset.txt" {
+		t.Errorf("unexpected assets %+v", upload.Assets)
+	}
+}
+
+func TestNewerSnapshot(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"1.0-20240101.120000-10", "1.0-20240101.120000-9", true},
+		{"1.0-20240101.120000-9", "1.0-20240101.120000-10", false},
+		{"1.0-20240102.000000-1", "1.0-20240101.235959-7", true},
+		{"1.0-20240101.120000-1", "1.0-SNAPSHOT", true},
+		{"1.0-SNAPSHOT", "1.0-20240101.120000-1", false},
+	}
+	for _, test := range tests {
+		if got := newerSnapshot(test.a, test.b, "1.0-"); got != test.expected {
+			t.Errorf("newerSnapshot(%s, %s): expected %v, got %v", test.a, test.b, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NPMUpload describes an npm package tarball
+type NPMUpload struct {
+	Asset UploadSource
+	// Overwrite uploads the package even when its version already exists,
+	// the repository's write policy must allow redeploying
+	Overwrite bool
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// NPMPackage identifies an uploaded package
+type NPMPackage struct {
+	// Scope without the leading @, empty for unscoped packages
+	Scope   string
+	Name    string
+	Version string
+}
+
+// readNPMPackage extracts package/package.json from the tarball in f
+func readNPMPackage(f *os.File) (NPMPackage, error) {
+	data, err := readTarGzFile(f, func(name string) bool {
+		return strings.TrimPrefix(name, "./") == "package/package.json"
+	})
+	if err != nil {
+		return NPMPackage{}, err
+	}
+
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return NPMPackage{}, errors.Wrap(err, "invalid package.json")
+	}
+	if manifest.Name == "" || manifest.Version == "" {
+		return NPMPackage{}, ErrMissingMetadata
+	}
+
+	pkg := NPMPackage{Name: manifest.Name, Version: manifest.Version}
+	if strings.HasPrefix(pkg.Name, "@") {
+		parts := strings.SplitN(pkg.Name[1:], "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return NPMPackage{}, fmt.Errorf("invalid scoped package name '%s'", manifest.Name)
+		}
+		pkg.Scope, pkg.Name = parts[0], parts[1]
+	}
+	return pkg, nil
+}
+
+// search parameters locating the package in the repository
+func (p NPMPackage) search(repositoryID string) SearchParameters {
+	return SearchParameters{
+		Repository: repositoryID,
+		Format:     "npm",
+		NPMScope:   p.Scope,
+		Name:       p.Name,
+		Version:    p.Version,
+	}
+}
+
+// match the exact component, scoped packages are grouped by their scope
+func (p NPMPackage) match(cpnt Component) bool {
+	return cpnt.Group == p.Scope && cpnt.Name == p.Name && cpnt.Version == p.Version
+}
+
+// UploadNPM package to a hosted npm repository
+func (c Client) UploadNPM(ctx context.Context, repositoryID string, upload NPMUpload) (*Component, error) {
+	if upload.Asset.open == nil {
+		return nil, errors.Wrap(ErrMissingFiles, "UploadNPM")
+	}
+
+	// Proxy and group repositories don't accept uploads
+	repo, err := c.repository(ctx, repositoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadNPM")
+	}
+	if repo.Type != RepositoryTypeHosted {
+		return nil, fmt.Errorf("UploadNPM: repository '%s' is a %s repository, packages can only be uploaded to hosted ones", repositoryID, repo.Type)
+	}
+
+	file, src, cleanup, err := localSource(upload.Asset)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadNPM")
+	}
+	defer cleanup()
+
+	pkg, err := readNPMPackage(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadNPM")
+	}
+
+	if !upload.Overwrite {
+		existing, _, err := c.SearchComponentsContext(ctx, pkg.search(repositoryID))
+		if err != nil {
+			return nil, errors.Wrap(err, "UploadNPM")
+		}
+		exists := false
+		for _, cpnt := range existing {
+			exists = exists || (cpnt.Repository == repositoryID && pkg.match(cpnt))
+		}
+		if exists {
+			return nil, errors.Wrapf(ErrConflict, "UploadNPM: version %s already exists", pkg.Version)
+		}
+	}
+
+	form := newMultipartForm()
+	form.AddFile("npm.asset", src)
+
+	err = c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, form, upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the package
+	return c.resolveComponent(ctx, repositoryID, pkg.search(repositoryID), pkg.match)
+}
+
+func (c Client) uploadNPMComponent(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	src, _ := p.source("npm.asset", p.NPMAsset)
+	return c.UploadNPM(ctx, rID, NPMUpload{Asset: src, Overwrite: p.NPMOverwrite, Progress: p.Progress})
+}
@@ -0,0 +1,63 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func testNPMTarball(t *testing.T) []byte {
+	return gzipData(t, tarArchive(t, map[string]string{
+		"package/package.json": `{"name":"@example/test","version":"0.0.1","main":"index.js"}`,
+		"package/index.js":     "",
+	}))
+}
+
+func TestUploadNPM(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "npm-hosted", Format: "npm", Group: "example", Name: "test", Version: "0.0.1"})
+	defer server.Close()
+	server.repositories = []Repository{
+		{Name: "npm-hosted", Format: "npm", Type: RepositoryTypeHosted},
+		{Name: "npm-proxy", Format: "npm", Type: RepositoryTypeProxy},
+	}
+
+	tarball := testNPMTarball(t)
+	upload := NPMUpload{Asset: ReaderSource("example-test-0.0.1.tgz", bytes.NewReader(tarball), -1)}
+
+	component, err := server.client().UploadNPM(context.Background(), "npm-hosted", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.files["npm.asset"] == "" {
+		t.Errorf("unexpected component %+v, files %v", component, server.files)
+	}
+	if server.search.Get("npm.scope") != "example" || server.search.Get("name") != "test" || server.search.Get("version") != "0.0.1" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+
+	// Now the version exists it is rejected, unless overwriting
+	if _, err := server.client().UploadNPM(context.Background(), "npm-hosted", upload); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	upload.Overwrite = true
+	if _, err := server.client().UploadNPM(context.Background(), "npm-hosted", upload); err != nil {
+		t.Errorf("expected overwrite to succeed, got %v", err)
+	}
+
+	// The UploadComponent parameters can ask for an overwrite as well
+	parameters := UploadParameters{
+		Sources:      map[string]UploadSource{"npm.asset": ReaderSource("example-test-0.0.1.tgz", bytes.NewReader(tarball), -1)},
+		NPMOverwrite: true,
+	}
+	if _, err := server.client().UploadComponentContext(context.Background(), "npm-hosted", parameters); err != nil {
+		t.Errorf("expected overwrite to succeed, got %v", err)
+	}
+
+	// Only hosted repositories accept uploads
+	upload.Asset = ReaderSource("example-test-0.0.1.tgz", bytes.NewReader(tarball), -1)
+	if _, err := server.client().UploadNPM(context.Background(), "npm-proxy", upload); err == nil {
+		t.Error("expected proxy repository to be rejected")
+	}
+}
@@ -0,0 +1,130 @@
+package nexus
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// nugetID allowed characters, as enforced by nuget.org
+	nugetID = regexp.MustCompile(`^\w+([_.-]\w+)*$`)
+	// nugetVersion is a SemVer 2.0 version, or a legacy four part version
+	nugetVersion = regexp.MustCompile(`^\d+(\.\d+){1,3}(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+)
+
+// NugetUpload describes a .nupkg file
+type NugetUpload struct {
+	Asset UploadSource
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// NugetPackage identifies an uploaded package
+type NugetPackage struct {
+	ID      string `xml:"metadata>id"`
+	Version string `xml:"metadata>version"`
+}
+
+// validate the package metadata before it is sent
+func (p NugetPackage) validate() error {
+	if p.ID == "" || p.Version == "" {
+		return ErrMissingMetadata
+	}
+	if len(p.ID) > 100 || !nugetID.MatchString(p.ID) {
+		return fmt.Errorf("invalid package id '%s'", p.ID)
+	}
+	if !nugetVersion.MatchString(p.Version) {
+		return fmt.Errorf("invalid package version '%s'", p.Version)
+	}
+	return nil
+}
+
+// normalizedVersion as NuGet servers store it: build metadata and leading
+// zeros dropped, padded to three parts and a zero fourth part removed,
+// e.g. 01.2 becomes 1.2.0 and 1.0.0.0 becomes 1.0.0
+func (p NugetPackage) normalizedVersion() string {
+	version := strings.SplitN(p.Version, "+", 2)[0]
+	release, prerelease := version, ""
+	if i := strings.Index(version, "-"); i >= 0 {
+		release, prerelease = version[:i], version[i:]
+	}
+
+	parts := strings.Split(release, ".")
+	for i, part := range parts {
+		if n, err := strconv.ParseUint(part, 10, 64); err == nil {
+			parts[i] = strconv.FormatUint(n, 10)
+		}
+	}
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	if len(parts) == 4 && parts[3] == "0" {
+		parts = parts[:3]
+	}
+	return strings.Join(parts, ".") + prerelease
+}
+
+// readNugetPackage extracts the .nuspec of the package in f
+func readNugetPackage(f *os.File) (NugetPackage, error) {
+	data, err := readZipFile(f, func(name string) bool {
+		return !strings.Contains(name, "/") && strings.HasSuffix(strings.ToLower(name), ".nuspec")
+	})
+	if err != nil {
+		return NugetPackage{}, err
+	}
+
+	var pkg NugetPackage
+	if err := xml.Unmarshal(data, &pkg); err != nil {
+		return NugetPackage{}, errors.Wrap(err, "invalid nuspec")
+	}
+	pkg.ID = strings.TrimSpace(pkg.ID)
+	pkg.Version = strings.TrimSpace(pkg.Version)
+	return pkg, pkg.validate()
+}
+
+// UploadNuget package to a nuget repository
+func (c Client) UploadNuget(ctx context.Context, repositoryID string, upload NugetUpload) (*Component, error) {
+	if upload.Asset.open == nil {
+		return nil, errors.Wrap(ErrMissingFiles, "UploadNuget")
+	}
+
+	file, src, cleanup, err := localSource(upload.Asset)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadNuget")
+	}
+	defer cleanup()
+
+	pkg, err := readNugetPackage(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadNuget")
+	}
+
+	form := newMultipartForm()
+	form.AddFile("nuget.asset", src)
+
+	err = c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, form, upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the package, nuget ids are case insensitive
+	version := pkg.normalizedVersion()
+	parameters := SearchParameters{
+		Format:  "nuget",
+		NugetID: pkg.ID,
+		Version: version,
+	}
+	return c.resolveComponent(ctx, repositoryID, parameters, matchNameVersionFold(pkg.ID, version))
+}
+
+func (c Client) uploadNugetComponent(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	src, _ := p.source("nuget.asset", p.NugetAsset)
+	return c.UploadNuget(ctx, rID, NugetUpload{Asset: src, Progress: p.Progress})
+}
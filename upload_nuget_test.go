@@ -0,0 +1,88 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+const testNuspec = `<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://schemas.microsoft.com/packaging/2013/05/nuspec.xsd">
+  <metadata>
+    <id>Example.Test</id>
+    <version>0.0.1</version>
+    <authors>Example</authors>
+  </metadata>
+</package>`
+
+func TestUploadNuget(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "nuget-hosted", Format: "nuget", Name: "Example.Test", Version: "0.0.1"})
+	defer server.Close()
+
+	nupkg := zipArchive(t, map[string]string{
+		"Example.Test.nuspec":           testNuspec,
+		"lib/net45/Example.Test.dll":    "",
+		"package/services/metadata.xml": "",
+	})
+	upload := NugetUpload{Asset: ReaderSource("Example.Test.0.0.1.nupkg", bytes.NewReader(nupkg), -1)}
+
+	component, err := server.client().UploadNuget(context.Background(), "nuget-hosted", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.files["nuget.asset"] != "Example.Test.0.0.1.nupkg" {
+		t.Errorf("unexpected component %+v, files %v", component, server.files)
+	}
+	if server.search.Get("nuget.id") != "Example.Test" || server.search.Get("version") != "0.0.1" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+}
+
+func TestUploadNugetInvalid(t *testing.T) {
+	server := newUploadServer(t, Component{})
+	defer server.Close()
+
+	nupkg := zipArchive(t, map[string]string{"Example.Test.nuspec": `<package><metadata><id>not valid!</id><version>0.0.1</version></metadata></package>`})
+	upload := NugetUpload{Asset: ReaderSource("Example.Test.0.0.1.nupkg", bytes.NewReader(nupkg), -1)}
+
+	if _, err := server.client().UploadNuget(context.Background(), "nuget-hosted", upload); err == nil {
+		t.Fatal("expected invalid id to be rejected")
+	}
+	if server.files != nil {
+		t.Error("nothing should have been uploaded")
+	}
+}
+
+func TestNugetNormalizedVersion(t *testing.T) {
+	for version, expected := range map[string]string{
+		"1.0":             "1.0.0",
+		"01.2.0":          "1.2.0",
+		"1.0.0.0":         "1.0.0",
+		"1.0.0.1":         "1.0.0.1",
+		"1.0.0-beta.1":    "1.0.0-beta.1",
+		"1.0-rc+build.5":  "1.0.0-rc",
+		"2.01.003+abcdef": "2.1.3",
+	} {
+		if got := (NugetPackage{Version: version}).normalizedVersion(); got != expected {
+			t.Errorf("%s: expected %s, got %s", version, expected, got)
+		}
+	}
+}
+
+func TestUploadNugetNormalizesVersion(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "nuget-hosted", Format: "nuget", Name: "Example.Test", Version: "1.2.0"})
+	defer server.Close()
+
+	nuspec := strings.Replace(testNuspec, "<version>0.0.1</version>", "<version>01.2</version>", 1)
+	nupkg := zipArchive(t, map[string]string{"Example.Test.nuspec": nuspec})
+	upload := NugetUpload{Asset: ReaderSource("Example.Test.01.2.nupkg", bytes.NewReader(nupkg), -1)}
+
+	component, err := server.client().UploadNuget(context.Background(), "nuget-hosted", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.search.Get("version") != "1.2.0" {
+		t.Errorf("unexpected component %+v, search %v", component, server.search)
+	}
+}
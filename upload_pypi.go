@@ -0,0 +1,157 @@
+package nexus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/textproto"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// wheelFilename as described by PEP 427:
+	// {distribution}-{version}(-{build tag})?-{python tag}-{abi tag}-{platform tag}.whl
+	wheelFilename = regexp.MustCompile(`^([A-Za-z0-9_.]+)-([A-Za-z0-9_.!+]+)(-\d[A-Za-z0-9_.]*)?-([A-Za-z0-9_.]+)-([A-Za-z0-9_.]+)-([A-Za-z0-9_.]+)\.whl$`)
+	// sdistFilename is {name}-{version}.tar.gz, or the legacy .zip
+	sdistFilename = regexp.MustCompile(`^([A-Za-z0-9_.-]+)-([A-Za-z0-9_.!+]+)\.(tar\.gz|zip)$`)
+	// pypiNameSeparators are collapsed when normalising names, see PEP 503
+	pypiNameSeparators = regexp.MustCompile(`[-_.]+`)
+)
+
+// PyPiUpload describes a wheel or source distribution
+type PyPiUpload struct {
+	Asset UploadSource
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// PyPiPackage identifies an uploaded distribution
+type PyPiPackage struct {
+	Name    string
+	Version string
+	// Wheel is false for source distributions
+	Wheel bool
+}
+
+// normalizePyPiName as described by PEP 503
+func normalizePyPiName(name string) string {
+	return strings.ToLower(pypiNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// parsePyPiFilename validates the filename of a distribution, returning the
+// name and version it claims to hold
+func parsePyPiFilename(filename string) (PyPiPackage, error) {
+	if m := wheelFilename.FindStringSubmatch(filename); m != nil {
+		return PyPiPackage{Name: m[1], Version: m[2], Wheel: true}, nil
+	}
+	if m := sdistFilename.FindStringSubmatch(filename); m != nil {
+		return PyPiPackage{Name: m[1], Version: m[2]}, nil
+	}
+	return PyPiPackage{}, fmt.Errorf("'%s' is neither a wheel nor a source distribution filename", filename)
+}
+
+// parsePyPiMetadata reads the Name and Version headers of a METADATA or
+// PKG-INFO file
+func parsePyPiMetadata(data []byte) (PyPiPackage, error) {
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(data)))
+	header, err := r.ReadMIMEHeader()
+	if err != nil && len(header) == 0 {
+		return PyPiPackage{}, err
+	}
+
+	pkg := PyPiPackage{
+		Name:    header.Get("Name"),
+		Version: header.Get("Version"),
+	}
+	if pkg.Name == "" || pkg.Version == "" {
+		return PyPiPackage{}, ErrMissingMetadata
+	}
+	return pkg, nil
+}
+
+// readPyPiPackage extracts the metadata of the distribution in f
+func readPyPiPackage(f *os.File, filename string) (PyPiPackage, error) {
+	claimed, err := parsePyPiFilename(filename)
+	if err != nil {
+		return PyPiPackage{}, err
+	}
+
+	var data []byte
+	switch {
+	case claimed.Wheel:
+		data, err = readZipFile(f, func(name string) bool {
+			return pathDepth(name) == 2 && strings.HasSuffix(path.Dir(name), ".dist-info") && path.Base(name) == "METADATA"
+		})
+	case strings.HasSuffix(filename, ".zip"):
+		data, err = readZipFile(f, isSdistPkgInfo)
+	default:
+		data, err = readTarGzFile(f, isSdistPkgInfo)
+	}
+	if err != nil {
+		return PyPiPackage{}, err
+	}
+
+	pkg, err := parsePyPiMetadata(data)
+	if err != nil {
+		return PyPiPackage{}, err
+	}
+	pkg.Wheel = claimed.Wheel
+
+	if normalizePyPiName(pkg.Name) != normalizePyPiName(claimed.Name) ||
+		strings.Replace(pkg.Version, "-", "_", -1) != strings.Replace(claimed.Version, "-", "_", -1) {
+		return PyPiPackage{}, fmt.Errorf("filename '%s' doesn't match package %s %s", filename, pkg.Name, pkg.Version)
+	}
+	return pkg, nil
+}
+
+// isSdistPkgInfo matches the top level PKG-INFO of a source distribution
+func isSdistPkgInfo(name string) bool {
+	return pathDepth(name) == 2 && path.Base(name) == "PKG-INFO"
+}
+
+// UploadPyPi distribution to a pypi repository
+func (c Client) UploadPyPi(ctx context.Context, repositoryID string, upload PyPiUpload) (*Component, error) {
+	if upload.Asset.open == nil {
+		return nil, errors.Wrap(ErrMissingFiles, "UploadPyPi")
+	}
+
+	file, src, cleanup, err := localSource(upload.Asset)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadPyPi")
+	}
+	defer cleanup()
+
+	pkg, err := readPyPiPackage(file, upload.Asset.Filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadPyPi")
+	}
+
+	form := newMultipartForm()
+	form.AddFile("pypi.asset", src)
+
+	err = c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, form, upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the package, pypi names are case insensitive
+	parameters := SearchParameters{
+		Format:  "pypi",
+		Name:    pkg.Name,
+		Version: pkg.Version,
+	}
+	return c.resolveComponent(ctx, repositoryID, parameters, func(cpnt Component) bool {
+		return normalizePyPiName(cpnt.Name) == normalizePyPiName(pkg.Name) && cpnt.Version == pkg.Version
+	})
+}
+
+func (c Client) uploadPyPiComponent(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	src, _ := p.source("pypi.asset", p.PyPiAsset)
+	return c.UploadPyPi(ctx, rID, PyPiUpload{Asset: src, Progress: p.Progress})
+}
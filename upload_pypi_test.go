@@ -0,0 +1,118 @@
+package nexus
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+)
+
+// zipArchive builds a zip file holding the given files
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// tarArchive builds an uncompressed tar stream holding the given files
+func tarArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for name, content := range files {
+		if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// gzipData compresses data
+func gzipData(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const testPyPiMetadata = "Metadata-Version: 2.1\nName: test-package\nVersion: 0.0.1\nSummary: testing\n\nLong description\n"
+
+func TestParsePyPiFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		valid    bool
+		wheel    bool
+	}{
+		{"test_package-0.0.1-py3-none-any.whl", true, true},
+		{"test_package-0.0.1-1-cp39-cp39-manylinux1_x86_64.whl", true, true},
+		{"test-package-0.0.1.tar.gz", true, false},
+		{"test_package-0.0.1.zip", true, false},
+		{"test_package-0.0.1.whl", false, false},
+		{"test_package.tar.gz", false, false},
+	}
+
+	for _, tt := range tests {
+		pkg, err := parsePyPiFilename(tt.filename)
+		if (err == nil) != tt.valid || pkg.Wheel != tt.wheel {
+			t.Errorf("%s: unexpected result %+v, %v", tt.filename, pkg, err)
+		}
+	}
+}
+
+func TestUploadPyPi(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "pypi-hosted", Format: "pypi", Name: "test-package", Version: "0.0.1"})
+	defer server.Close()
+
+	wheel := zipArchive(t, map[string]string{
+		"test_package/__init__.py":              "",
+		"test_package-0.0.1.dist-info/METADATA": testPyPiMetadata,
+	})
+	upload := PyPiUpload{Asset: ReaderSource("test_package-0.0.1-py3-none-any.whl", bytes.NewReader(wheel), int64(len(wheel)))}
+
+	component, err := server.client().UploadPyPi(context.Background(), "pypi-hosted", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" {
+		t.Errorf("unexpected component %+v", component)
+	}
+	if server.files["pypi.asset"] != "test_package-0.0.1-py3-none-any.whl" {
+		t.Errorf("unexpected files %v", server.files)
+	}
+	if server.search.Get("name") != "test-package" || server.search.Get("version") != "0.0.1" || server.search.Get("repository") != "pypi-hosted" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+}
+
+func TestUploadPyPiSdistMismatch(t *testing.T) {
+	server := newUploadServer(t, Component{})
+	defer server.Close()
+
+	sdist := gzipData(t, tarArchive(t, map[string]string{"other-0.0.1/PKG-INFO": testPyPiMetadata}))
+	upload := PyPiUpload{Asset: ReaderSource("other-0.0.1.tar.gz", bytes.NewReader(sdist), -1)}
+
+	if _, err := server.client().UploadPyPi(context.Background(), "pypi-hosted", upload); err == nil {
+		t.Fatal("expected mismatched metadata to be rejected")
+	}
+	if server.files != nil {
+		t.Error("nothing should have been uploaded")
+	}
+}
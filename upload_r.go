@@ -0,0 +1,100 @@
+package nexus
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RUpload describes an R package, either a source .tar.gz or a binary
+// .tgz/.zip build
+type RUpload struct {
+	Asset UploadSource
+	// PathID the package is stored at, defaults to src/contrib/{filename}
+	PathID string
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// RPackage identifies an uploaded package
+type RPackage struct {
+	Package string
+	Version string
+}
+
+// readRPackage extracts the {package}/DESCRIPTION file of the package in f
+func readRPackage(f *os.File, filename string) (RPackage, error) {
+	isDescription := func(name string) bool {
+		return pathDepth(name) == 2 && path.Base(name) == "DESCRIPTION"
+	}
+
+	var data []byte
+	var err error
+	if strings.HasSuffix(filename, ".zip") {
+		data, err = readZipFile(f, isDescription)
+	} else {
+		data, err = readTarGzFile(f, isDescription)
+	}
+	if err != nil {
+		return RPackage{}, err
+	}
+
+	// DESCRIPTION files share the debian control file syntax
+	fields := parseControl(data)
+	pkg := RPackage{
+		Package: fields["Package"],
+		Version: fields["Version"],
+	}
+	if pkg.Package == "" || pkg.Version == "" {
+		return RPackage{}, ErrMissingMetadata
+	}
+	return pkg, nil
+}
+
+// UploadR package to an r repository
+func (c Client) UploadR(ctx context.Context, repositoryID string, upload RUpload) (*Component, error) {
+	if upload.Asset.open == nil {
+		return nil, errors.Wrap(ErrMissingFiles, "UploadR")
+	}
+
+	file, src, cleanup, err := localSource(upload.Asset)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadR")
+	}
+	defer cleanup()
+
+	pkg, err := readRPackage(file, upload.Asset.Filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadR")
+	}
+
+	pathID := upload.PathID
+	if pathID == "" {
+		pathID = path.Join("src/contrib", upload.Asset.Filename)
+	}
+
+	form := newMultipartForm()
+	form.AddFile("r.asset", src)
+	form.WriteField("r.asset.pathId", pathID)
+
+	err = c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, form, upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the package
+	parameters := SearchParameters{
+		Format:  "r",
+		Name:    pkg.Package,
+		Version: pkg.Version,
+	}
+	return c.resolveComponent(ctx, repositoryID, parameters, matchNameVersion(pkg.Package, pkg.Version))
+}
+
+func (c Client) uploadRComponent(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	src, _ := p.source("r.asset", p.RAsset)
+	return c.UploadR(ctx, rID, RUpload{Asset: src, PathID: p.RAssetPathID, Progress: p.Progress})
+}
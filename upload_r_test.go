@@ -0,0 +1,29 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestUploadR(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "r-hosted", Format: "r", Name: "test", Version: "0.0.1"})
+	defer server.Close()
+
+	pkg := gzipData(t, tarArchive(t, map[string]string{
+		"test/DESCRIPTION": "Package: test\nType: Package\nVersion: 0.0.1\nDescription: testing\n    more testing\n",
+		"test/R/test.R":    "",
+	}))
+	upload := RUpload{Asset: ReaderSource("test_0.0.1.tar.gz", bytes.NewReader(pkg), -1)}
+
+	component, err := server.client().UploadR(context.Background(), "r-hosted", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.fields["r.asset.pathId"] != "src/contrib/test_0.0.1.tar.gz" {
+		t.Errorf("unexpected component %+v, fields %v", component, server.fields)
+	}
+	if server.search.Get("name") != "test" || server.search.Get("version") != "0.0.1" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+}
@@ -0,0 +1,110 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RawAsset is a single file of a raw component
+type RawAsset struct {
+	Source UploadSource
+	// Filename the asset is stored as within the directory, defaults to
+	// the source's filename
+	Filename string
+}
+
+// RawUpload describes a raw component made up of any number of assets
+type RawUpload struct {
+	Directory string
+	Assets    []RawAsset
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// rawUpload adapts the fixed raw.asset1..3 fields
+func (p UploadParameters) rawUpload() RawUpload {
+	u := RawUpload{
+		Directory: p.RawDirectory,
+		Progress:  p.Progress,
+	}
+
+	assets := []struct{ path, filename string }{
+		{p.RawAsset1, p.RawAsset1Filename},
+		{p.RawAsset2, p.RawAsset2Filename},
+		{p.RawAsset3, p.RawAsset3Filename},
+	}
+	for i, a := range assets {
+		if src, ok := p.source(fmt.Sprintf("raw.asset%d", i+1), a.path); ok {
+			u.Assets = append(u.Assets, RawAsset{Source: src, Filename: a.filename})
+		}
+	}
+	return u
+}
+
+func (a RawAsset) filename() string {
+	if a.Filename != "" {
+		return a.Filename
+	}
+	return a.Source.Filename
+}
+
+// path of the asset within the repository, which raw components are named by
+func (u RawUpload) path(a RawAsset) string {
+	return path.Join(strings.Trim(u.Directory, "/"), a.filename())
+}
+
+// validate the upload before anything is sent
+func (u RawUpload) validate() error {
+	if len(u.Assets) == 0 {
+		return ErrMissingFiles
+	}
+	if u.Directory == "" {
+		return fmt.Errorf("missing upload directory")
+	}
+	return nil
+}
+
+// form encodes the upload, numbering the assets raw.asset1..N
+func (u RawUpload) form() *multipartForm {
+	form := newMultipartForm()
+
+	for i, a := range u.Assets {
+		label := fmt.Sprintf("raw.asset%d", i+1)
+		form.AddFile(label, a.Source)
+		form.WriteField(label+".filename", a.filename())
+	}
+
+	form.WriteField("raw.directory", u.Directory)
+	return form
+}
+
+// UploadRaw component to a raw repository
+func (c Client) UploadRaw(ctx context.Context, repositoryID string, upload RawUpload) (*Component, error) {
+	if err := upload.validate(); err != nil {
+		return nil, errors.Wrap(err, "UploadRaw")
+	}
+
+	err := c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, upload.form(), upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the artifact, every raw asset is its own component named by its
+	// path so the first one is returned
+	name := upload.path(upload.Assets[0])
+	parameters := SearchParameters{
+		Format: "raw",
+		Name:   name,
+	}
+	return c.resolveComponent(ctx, repositoryID, parameters, func(cpnt Component) bool {
+		return strings.TrimPrefix(cpnt.Name, "/") == name
+	})
+}
+
+func (c Client) uploadRawComponent(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	return c.UploadRaw(ctx, rID, p.rawUpload())
+}
@@ -0,0 +1,148 @@
+package nexus
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RubyGemsUpload describes a .gem file
+type RubyGemsUpload struct {
+	Asset UploadSource
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// RubyGem identifies an uploaded gem
+type RubyGem struct {
+	Name     string
+	Version  string
+	Platform string
+}
+
+// parseGemSpec reads the name, version and platform out of the YAML
+// serialised Gem::Specification found in metadata.gz. Only the top level
+// keys are of interest, so a full YAML parser isn't needed.
+func parseGemSpec(data []byte) (RubyGem, error) {
+	var gem RubyGem
+	inVersion := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		indented := strings.HasPrefix(line, " ")
+		key, value := splitYAMLLine(line)
+
+		switch {
+		case inVersion && indented && key == "version":
+			gem.Version = value
+			inVersion = false
+		case indented:
+		case key == "name":
+			gem.Name = value
+		case key == "version":
+			// Either inline or a nested !ruby/object:Gem::Version
+			if value != "" && !strings.HasPrefix(value, "!") {
+				gem.Version = value
+			} else {
+				inVersion = true
+			}
+		case key == "platform":
+			gem.Platform = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return RubyGem{}, err
+	}
+
+	if gem.Name == "" || gem.Version == "" {
+		return RubyGem{}, ErrMissingMetadata
+	}
+	if gem.Platform == "" {
+		gem.Platform = "ruby"
+	}
+	return gem, nil
+}
+
+// splitYAMLLine splits a simple "key: value" line, unquoting the value
+func splitYAMLLine(line string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	value := strings.TrimSpace(parts[1])
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	return strings.TrimSpace(parts[0]), value
+}
+
+// readRubyGem extracts the specification of the gem in f
+func readRubyGem(f *os.File) (RubyGem, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return RubyGem{}, err
+	}
+	data, err := readTarFile(f, func(name string) bool { return name == "metadata.gz" })
+	if err != nil {
+		return RubyGem{}, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return RubyGem{}, err
+	}
+	defer gz.Close()
+
+	spec, err := ioutil.ReadAll(io.LimitReader(gz, maxMetadataSize))
+	if err != nil {
+		return RubyGem{}, err
+	}
+	return parseGemSpec(spec)
+}
+
+// UploadRubyGems gem to a rubygems repository
+func (c Client) UploadRubyGems(ctx context.Context, repositoryID string, upload RubyGemsUpload) (*Component, error) {
+	if upload.Asset.open == nil {
+		return nil, errors.Wrap(ErrMissingFiles, "UploadRubyGems")
+	}
+
+	file, src, cleanup, err := localSource(upload.Asset)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadRubyGems")
+	}
+	defer cleanup()
+
+	gem, err := readRubyGem(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadRubyGems")
+	}
+
+	form := newMultipartForm()
+	form.AddFile("rubygems.asset", src)
+
+	err = c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, form, upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the gem
+	parameters := SearchParameters{
+		Format:           "rubygems",
+		Name:             gem.Name,
+		Version:          gem.Version,
+		RubyGemsPlatform: gem.Platform,
+	}
+	return c.resolveComponent(ctx, repositoryID, parameters, matchNameVersion(gem.Name, gem.Version))
+}
+
+func (c Client) uploadRubyGemsComponent(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	src, _ := p.source("rubygems.asset", p.RubyGemsAsset)
+	return c.UploadRubyGems(ctx, rID, RubyGemsUpload{Asset: src, Progress: p.Progress})
+}
@@ -0,0 +1,58 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+const testGemSpec = `--- !ruby/object:Gem::Specification
+name: test_gem
+version: !ruby/object:Gem::Version
+  version: 0.0.1
+platform: x86_64-linux
+authors:
+- Example
+dependencies:
+- !ruby/object:Gem::Dependency
+  name: rake
+  requirement: !ruby/object:Gem::Requirement
+    requirements:
+    - - ">="
+      - !ruby/object:Gem::Version
+        version: '0'
+  type: :runtime
+`
+
+func TestParseGemSpec(t *testing.T) {
+	gem, err := parseGemSpec([]byte(testGemSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gem != (RubyGem{Name: "test_gem", Version: "0.0.1", Platform: "x86_64-linux"}) {
+		t.Errorf("unexpected gem %+v", gem)
+	}
+}
+
+func TestUploadRubyGems(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "rubygems-hosted", Format: "rubygems", Name: "test_gem", Version: "0.0.1"})
+	defer server.Close()
+
+	gem := tarArchive(t, map[string]string{
+		"metadata.gz":       string(gzipData(t, []byte(testGemSpec))),
+		"data.tar.gz":       string(gzipData(t, tarArchive(t, map[string]string{"lib/test_gem.rb": ""}))),
+		"checksums.yaml.gz": string(gzipData(t, []byte("---\n"))),
+	})
+	upload := RubyGemsUpload{Asset: ReaderSource("test_gem-0.0.1-x86_64-linux.gem", bytes.NewReader(gem), -1)}
+
+	component, err := server.client().UploadRubyGems(context.Background(), "rubygems-hosted", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.files["rubygems.asset"] == "" {
+		t.Errorf("unexpected component %+v, files %v", component, server.files)
+	}
+	if server.search.Get("name") != "test_gem" || server.search.Get("rubygems.platform") != "x86_64-linux" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+}
@@ -0,0 +1,181 @@
+package nexus
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// YumUpload describes a .rpm package
+type YumUpload struct {
+	Asset UploadSource
+	// Directory the package is stored in within the repository
+	Directory string
+	// Filename the package is stored as, defaults to the source's filename
+	Filename string
+	// Progress is notified as the upload is sent
+	Progress UploadProgress
+}
+
+// RPMPackage identifies an uploaded package
+type RPMPackage struct {
+	Name    string
+	Version string
+	Release string
+	Arch    string
+}
+
+// rpm header tags of interest
+const (
+	rpmTagName    = 1000
+	rpmTagVersion = 1001
+	rpmTagRelease = 1002
+	rpmTagArch    = 1022
+
+	rpmTypeString = 6
+	rpmLeadSize   = 96
+)
+
+var rpmHeaderMagic = []byte{0x8e, 0xad, 0xe8, 0x01}
+
+// readRPMHeader reads a header structure, returning the string tags. The
+// signature header is padded to a multiple of eight bytes.
+func readRPMHeader(r *bufio.Reader, pad bool) (map[int32]string, error) {
+	intro := make([]byte, 16)
+	if _, err := io.ReadFull(r, intro); err != nil {
+		return nil, err
+	}
+	if string(intro[:4]) != string(rpmHeaderMagic) {
+		return nil, fmt.Errorf("invalid rpm header")
+	}
+	count := binary.BigEndian.Uint32(intro[8:12])
+	size := binary.BigEndian.Uint32(intro[12:16])
+	if count > 1<<16 || size > maxMetadataSize*32 {
+		return nil, fmt.Errorf("rpm header too large")
+	}
+
+	type entry struct{ Tag, Type, Offset, Count int32 }
+	entries := make([]entry, count)
+	if err := binary.Read(r, binary.BigEndian, entries); err != nil {
+		return nil, err
+	}
+
+	store := make([]byte, size)
+	if _, err := io.ReadFull(r, store); err != nil {
+		return nil, err
+	}
+	if pad && size%8 != 0 {
+		if _, err := r.Discard(int(8 - size%8)); err != nil {
+			return nil, err
+		}
+	}
+
+	tags := map[int32]string{}
+	for _, e := range entries {
+		if e.Type != rpmTypeString || e.Offset < 0 || int(e.Offset) >= len(store) {
+			continue
+		}
+		value := store[e.Offset:]
+		if end := strings.IndexByte(string(value), 0); end >= 0 {
+			value = value[:end]
+		}
+		tags[e.Tag] = string(value)
+	}
+	return tags, nil
+}
+
+// readRPMPackage extracts the name, version, release and arch of the package in f
+func readRPMPackage(f *os.File) (RPMPackage, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return RPMPackage{}, err
+	}
+	r := bufio.NewReader(f)
+
+	lead := make([]byte, rpmLeadSize)
+	if _, err := io.ReadFull(r, lead); err != nil || string(lead[:4]) != "\xed\xab\xee\xdb" {
+		return RPMPackage{}, fmt.Errorf("not an rpm package")
+	}
+
+	// Signature first, then the header holding the package details
+	if _, err := readRPMHeader(r, true); err != nil {
+		return RPMPackage{}, err
+	}
+	tags, err := readRPMHeader(r, false)
+	if err != nil {
+		return RPMPackage{}, err
+	}
+
+	pkg := RPMPackage{
+		Name:    tags[rpmTagName],
+		Version: tags[rpmTagVersion],
+		Release: tags[rpmTagRelease],
+		Arch:    tags[rpmTagArch],
+	}
+	if pkg.Name == "" || pkg.Version == "" {
+		return RPMPackage{}, ErrMissingMetadata
+	}
+	return pkg, nil
+}
+
+// UploadYum package to a yum repository
+func (c Client) UploadYum(ctx context.Context, repositoryID string, upload YumUpload) (*Component, error) {
+	if upload.Asset.open == nil {
+		return nil, errors.Wrap(ErrMissingFiles, "UploadYum")
+	}
+
+	file, src, cleanup, err := localSource(upload.Asset)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadYum")
+	}
+	defer cleanup()
+
+	pkg, err := readRPMPackage(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadYum")
+	}
+
+	filename := upload.Filename
+	if filename == "" {
+		filename = upload.Asset.Filename
+	}
+
+	form := newMultipartForm()
+	form.AddFile("yum.asset", src)
+	form.WriteField("yum.asset.filename", filename)
+	if upload.Directory != "" {
+		form.WriteField("yum.directory", upload.Directory)
+	}
+
+	err = c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, form, upload.Progress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the package, yum versions include the release
+	version := pkg.Version
+	if pkg.Release != "" {
+		version += "-" + pkg.Release
+	}
+	parameters := SearchParameters{
+		Format:  "yum",
+		Name:    pkg.Name,
+		Version: version,
+	}
+	return c.resolveComponent(ctx, repositoryID, parameters, matchNameVersion(pkg.Name, version))
+}
+
+func (c Client) uploadYumComponent(ctx context.Context, rID string, p UploadParameters) (*Component, error) {
+	src, _ := p.source("yum.asset", p.YumAsset)
+	return c.UploadYum(ctx, rID, YumUpload{
+		Asset:     src,
+		Directory: p.YumDirectory,
+		Filename:  p.YumAssetFilename,
+		Progress:  p.Progress,
+	})
+}
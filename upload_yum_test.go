@@ -0,0 +1,62 @@
+package nexus
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+)
+
+// rpmHeader encodes a header structure holding the given string tags
+func rpmHeader(tags map[int32]string, pad bool) []byte {
+	var index, store bytes.Buffer
+	for tag, value := range tags {
+		binary.Write(&index, binary.BigEndian, []int32{tag, rpmTypeString, int32(store.Len()), 1})
+		store.WriteString(value)
+		store.WriteByte(0)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(rpmHeaderMagic)
+	buf.Write(make([]byte, 4))
+	binary.Write(&buf, binary.BigEndian, []uint32{uint32(len(tags)), uint32(store.Len())})
+	buf.Write(index.Bytes())
+	buf.Write(store.Bytes())
+	if pad && store.Len()%8 != 0 {
+		buf.Write(make([]byte, 8-store.Len()%8))
+	}
+	return buf.Bytes()
+}
+
+func TestUploadYum(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Repository: "yum-hosted", Format: "yum", Name: "test", Version: "0.0.1-1.el7"})
+	defer server.Close()
+
+	var rpm bytes.Buffer
+	lead := make([]byte, rpmLeadSize)
+	copy(lead, "\xed\xab\xee\xdb")
+	rpm.Write(lead)
+	rpm.Write(rpmHeader(map[int32]string{1004: "sig"}, true))
+	rpm.Write(rpmHeader(map[int32]string{
+		rpmTagName:    "test",
+		rpmTagVersion: "0.0.1",
+		rpmTagRelease: "1.el7",
+		rpmTagArch:    "x86_64",
+	}, false))
+
+	upload := YumUpload{
+		Asset:     ReaderSource("test-0.0.1-1.el7.x86_64.rpm", bytes.NewReader(rpm.Bytes()), -1),
+		Directory: "/7/os/x86_64",
+	}
+
+	component, err := server.client().UploadYum(context.Background(), "yum-hosted", upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.fields["yum.asset.filename"] != "test-0.0.1-1.el7.x86_64.rpm" || server.fields["yum.directory"] != "/7/os/x86_64" {
+		t.Errorf("unexpected component %+v, fields %v", component, server.fields)
+	}
+	if server.search.Get("name") != "test" || server.search.Get("version") != "0.0.1-1.el7" {
+		t.Errorf("unexpected search %v", server.search)
+	}
+}
@@ -0,0 +1,106 @@
+package nexus
+
+import (
+	"context"
+	"sync"
+)
+
+// Uploader uploads components to repositories of a single format
+type Uploader interface {
+	Upload(ctx context.Context, c Client, repositoryID string, parameters UploadParameters) (*Component, error)
+}
+
+// UploaderFunc adapts a function to an Uploader
+type UploaderFunc func(ctx context.Context, c Client, repositoryID string, parameters UploadParameters) (*Component, error)
+
+// Upload calls f
+func (f UploaderFunc) Upload(ctx context.Context, c Client, repositoryID string, parameters UploadParameters) (*Component, error) {
+	return f(ctx, c, repositoryID, parameters)
+}
+
+var (
+	uploadersMu sync.RWMutex
+	uploaders   = map[string]Uploader{}
+)
+
+// RegisterUploader makes an Uploader available to every Client for the given
+// repository format, replacing any existing one, including the built in ones
+func RegisterUploader(format string, uploader Uploader) {
+	uploadersMu.Lock()
+	defer uploadersMu.Unlock()
+
+	if uploader == nil {
+		delete(uploaders, format)
+		return
+	}
+	uploaders[format] = uploader
+}
+
+// WithUploader uses the Uploader for the given repository format on this
+// client only, taking precedence over the registered ones. A nil Uploader
+// drops an earlier WithUploader for the format.
+func WithUploader(format string, uploader Uploader) Option {
+	return func(c *Client) error {
+		m := make(map[string]Uploader, len(c.uploaders)+1)
+		for k, v := range c.uploaders {
+			m[k] = v
+		}
+		if uploader == nil {
+			delete(m, format)
+		} else {
+			m[format] = uploader
+		}
+		c.uploaders = m
+		return nil
+	}
+}
+
+// uploader returns the Uploader handling the given format
+func (c Client) uploader(format string) (Uploader, bool) {
+	if u, ok := c.uploaders[format]; ok {
+		return u, true
+	}
+
+	uploadersMu.RLock()
+	defer uploadersMu.RUnlock()
+	u, ok := uploaders[format]
+	return u, ok
+}
+
+// builtinUploader adapts one of the client's own upload methods
+func builtinUploader(fn func(Client, context.Context, string, UploadParameters) (*Component, error)) Uploader {
+	return UploaderFunc(func(ctx context.Context, c Client, repositoryID string, parameters UploadParameters) (*Component, error) {
+		return fn(c, ctx, repositoryID, parameters)
+	})
+}
+
+func init() {
+	RegisterUploader("maven2", builtinUploader(Client.uploadMaven2Component))
+	RegisterUploader("raw", builtinUploader(Client.uploadRawComponent))
+	RegisterUploader("pypi", builtinUploader(Client.uploadPyPiComponent))
+	RegisterUploader("rubygems", builtinUploader(Client.uploadRubyGemsComponent))
+	RegisterUploader("nuget", builtinUploader(Client.uploadNugetComponent))
+	RegisterUploader("npm", builtinUploader(Client.uploadNPMComponent))
+	RegisterUploader("apt", builtinUploader(Client.uploadAptComponent))
+	RegisterUploader("yum", builtinUploader(Client.uploadYumComponent))
+	RegisterUploader("helm", builtinUploader(Client.uploadHelmComponent))
+	RegisterUploader("r", builtinUploader(Client.uploadRComponent))
+}
+
+// UploadForm posts a multipart upload to the components endpoint, for
+// Uploaders handling formats the library doesn't know about. Fields and files
+// are keyed by their form field name, e.g. "conan.asset".
+func (c Client) UploadForm(ctx context.Context, repositoryID string, fields map[string]string, files map[string]UploadSource, progress UploadProgress) error {
+	if len(files) == 0 {
+		return ErrMissingFiles
+	}
+
+	form := newMultipartForm()
+	for name, src := range files {
+		form.AddFile(name, src)
+	}
+	for name, value := range fields {
+		form.WriteField(name, value)
+	}
+	return c.makeMultiPartRequest(ctx, "POST", "/components", map[string]interface{}{"repository": repositoryID}, form, progress, nil)
+}
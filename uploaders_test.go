@@ -0,0 +1,65 @@
+package nexus
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCustomUploader(t *testing.T) {
+	server := newUploadServer(t, Component{ID: "abc", Format: "conan"})
+	defer server.Close()
+	server.repositories = []Repository{{Name: "conan-hosted", Format: "conan", Type: "hosted"}}
+
+	conan := UploaderFunc(func(ctx context.Context, c Client, repositoryID string, p UploadParameters) (*Component, error) {
+		fields := map[string]string{"conan.reference": p.Fields["reference"]}
+		if err := c.UploadForm(ctx, repositoryID, fields, p.Sources, p.Progress); err != nil {
+			return nil, err
+		}
+		return &Component{ID: "abc", Format: "conan"}, nil
+	})
+
+	params := UploadParameters{
+		Fields:  map[string]string{"reference": "test/0.0.1"},
+		Sources: map[string]UploadSource{"conan.asset": ReaderSource("conanfile.py", strings.NewReader(testContent), -1)},
+	}
+
+	// Unknown until an uploader is provided
+	if _, err := server.client().UploadComponent("conan-hosted", params); !errors.Is(err, ErrUnknownRepoFormat) {
+		t.Fatalf("expected ErrUnknownRepoFormat, got %v", err)
+	}
+
+	c, _ := New(server.URL, WithBasicAuth("admin", "admin123"), WithUploader("conan", conan))
+	component, err := c.UploadComponent("conan-hosted", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.ID != "abc" || server.fields["conan.reference"] != "test/0.0.1" || server.files["conan.asset"] != "conanfile.py" {
+		t.Errorf("unexpected component %+v, fields %v, files %v", component, server.fields, server.files)
+	}
+
+	// A nil uploader drops the client's own, rather than panicking on upload
+	c, _ = New(server.URL, WithBasicAuth("admin", "admin123"), WithUploader("conan", conan), WithUploader("conan", nil))
+	if _, err := c.UploadComponent("conan-hosted", params); !errors.Is(err, ErrUnknownRepoFormat) {
+		t.Errorf("expected ErrUnknownRepoFormat, got %v", err)
+	}
+}
+
+func TestRegisterUploaderOverridesBuiltin(t *testing.T) {
+	server := newUploadServer(t, Component{})
+	defer server.Close()
+	server.repositories = []Repository{{Name: "raw-repo", Format: "raw", Type: "hosted"}}
+
+	called := false
+	RegisterUploader("raw", UploaderFunc(func(ctx context.Context, c Client, repositoryID string, p UploadParameters) (*Component, error) {
+		called = true
+		return &Component{}, nil
+	}))
+	defer RegisterUploader("raw", builtinUploader(Client.uploadRawComponent))
+
+	if _, err := server.client().UploadComponent("raw-repo", UploadParameters{}); err != nil || !called {
+		t.Errorf("expected the registered uploader to be used, got %v", err)
+	}
+}